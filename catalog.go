@@ -0,0 +1,350 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"strings"
+	"sync"
+)
+
+// Standard CRC-16 algorithms from the CRC RevEng catalogue. The check value
+// of each algorithm is its checksum of the ASCII string "123456789".
+var (
+	// ARC is CRC-16/ARC, used by LHA and ARC (check 0xBB3D).
+	ARC = Params{Name: "ARC", Poly: 0x8005, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000}
+	// Modbus is CRC-16/MODBUS (check 0x4B37).
+	Modbus = Params{Name: "MODBUS", Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000}
+	// USB is CRC-16/USB (check 0xB4C8).
+	USB = Params{Name: "USB", Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff}
+	// MAXIM is CRC-16/MAXIM-DOW, used by 1-Wire devices (check 0x44C2).
+	MAXIM = Params{Name: "MAXIM", Poly: 0x8005, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0xffff}
+	// KERMIT is CRC-16/KERMIT, also known as CRC-CCITT (check 0x2189).
+	KERMIT = Params{Name: "KERMIT", Poly: 0x1021, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000}
+	// X25 is CRC-16/IBM-SDLC, used by X.25 and HDLC (check 0x906E).
+	X25 = Params{Name: "X-25", Poly: 0x1021, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff}
+	// XMODEM is CRC-16/XMODEM (check 0x31C3).
+	XMODEM = Params{Name: "XMODEM", Poly: 0x1021, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// CCITTFalse is CRC-16/IBM-3740, commonly known as CRC-16/CCITT-FALSE
+	// (check 0x29B1).
+	CCITTFalse = Params{Name: "CCITT-FALSE", Poly: 0x1021, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// GENIBUS is CRC-16/GENIBUS (check 0xD64E).
+	GENIBUS = Params{Name: "GENIBUS", Poly: 0x1021, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0xffff}
+	// DNP is CRC-16/DNP, used by the DNP3 protocol (check 0xEA82).
+	DNP = Params{Name: "DNP", Poly: 0x3d65, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0xffff}
+	// T10DIF is CRC-16/T10-DIF, the guard tag of SCSI data integrity
+	// fields (check 0xD0DB).
+	T10DIF = Params{Name: "T10-DIF", Poly: 0x8bb7, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// Profibus is CRC-16/PROFIBUS, used by PROFIBUS and IEC 61158
+	// (check 0xA819).
+	Profibus = Params{Name: "PROFIBUS", Poly: 0x1dcf, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0xffff}
+	// GSM is CRC-16/GSM, used by GSM mobile networks (check 0xCE3C).
+	GSM = Params{Name: "GSM", Poly: 0x1021, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0xffff}
+	// MCRF4XX is CRC-16/MCRF4XX, used by Microchip RFID tags
+	// (check 0x6F91).
+	MCRF4XX = Params{Name: "MCRF4XX", Poly: 0x1021, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000}
+	// RIELLO is CRC-16/RIELLO, used by Riello UPS units (check 0x63D0).
+	RIELLO = Params{Name: "RIELLO", Poly: 0x1021, Init: 0xb2aa, RefIn: true, RefOut: true, XorOut: 0x0000}
+	// CDMA2000 is CRC-16/CDMA2000, used by CDMA2000 mobile networks
+	// (check 0x4C06).
+	CDMA2000 = Params{Name: "CDMA2000", Poly: 0xc867, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// TELEDISK is CRC-16/TELEDISK, used by Teledisk disk images
+	// (check 0x0FB3).
+	TELEDISK = Params{Name: "TELEDISK", Poly: 0xa097, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// OpenSafetyA is CRC-16/OPENSAFETY-A, used by the openSAFETY
+	// protocol (check 0x5D38).
+	OpenSafetyA = Params{Name: "OPENSAFETY-A", Poly: 0x5935, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// OpenSafetyB is CRC-16/OPENSAFETY-B, used by the openSAFETY
+	// protocol (check 0x20FE).
+	OpenSafetyB = Params{Name: "OPENSAFETY-B", Poly: 0x755b, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// LJ1200 is CRC-16/LJ1200, used by LJ1200 alarm systems (check 0xBDF4).
+	LJ1200 = Params{Name: "LJ1200", Poly: 0x6f63, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// M17 is CRC-16/M17, used by the M17 digital radio protocol
+	// (check 0x772B).
+	M17 = Params{Name: "M17", Poly: 0x5935, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0x0000}
+	// NRSC5 is CRC-16/NRSC-5, used by NRSC-5 HD Radio (check 0xA066).
+	NRSC5 = Params{Name: "NRSC-5", Poly: 0x080b, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000}
+	// DECTR is CRC-16/DECT-R, the R-CRC of the DECT A-field (check 0x007E).
+	DECTR = Params{Name: "DECT-R", Poly: 0x0589, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0001}
+	// DECTX is CRC-16/DECT-X, the X-CRC of the DECT B-field (check 0x007F).
+	DECTX = Params{Name: "DECT-X", Poly: 0x0589, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}
+)
+
+// catalog lists the catalog algorithms in the order tried by Identify.
+var catalog = []*Params{
+	&ARC, &Modbus, &USB, &MAXIM, &KERMIT, &X25, &XMODEM, &CCITTFalse,
+	&GENIBUS, &DNP, &T10DIF, &Profibus, &GSM, &MCRF4XX, &RIELLO, &CDMA2000,
+	&TELEDISK, &OpenSafetyA, &OpenSafetyB, &LJ1200, &M17, &NRSC5, &DECTR,
+	&DECTX,
+}
+
+// Identify returns the names of the catalog algorithms for which expected
+// is the checksum of data, to help find the algorithm used by a device.
+// Short messages may match several algorithms by chance.
+func Identify(data []byte, expected uint16) []string {
+	var names []string
+	for _, p := range catalog {
+		if p.checksum(TableFor(*p), data) == expected {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// byName maps the normalized names of the catalog algorithms, and their
+// common aliases, to their parameters.
+var byName = map[string]Params{
+	"arc":         ARC,
+	"modbus":      Modbus,
+	"usb":         USB,
+	"maxim":       MAXIM,
+	"maximdow":    MAXIM,
+	"kermit":      KERMIT,
+	"x25":         X25,
+	"ibmsdlc":     X25,
+	"xmodem":      XMODEM,
+	"ccittfalse":  CCITTFalse,
+	"ibm3740":     CCITTFalse,
+	"genibus":     GENIBUS,
+	"dnp":         DNP,
+	"t10dif":      T10DIF,
+	"profibus":    Profibus,
+	"gsm":         GSM,
+	"mcrf4xx":     MCRF4XX,
+	"riello":      RIELLO,
+	"cdma2000":    CDMA2000,
+	"teledisk":    TELEDISK,
+	"opensafetya": OpenSafetyA,
+	"opensafetyb": OpenSafetyB,
+	"lj1200":      LJ1200,
+	"m17":         M17,
+	"nrsc5":       NRSC5,
+	"dectr":       DECTR,
+	"dectx":       DECTX,
+}
+
+// ByName returns the parameters of the catalog algorithm with the given
+// name, such as "modbus" or "CRC-16/CCITT-FALSE". Case, hyphens and the
+// "CRC-16/" prefix are ignored.
+func ByName(name string) (Params, bool) {
+	p, ok := byName[normalizeName(name)]
+	return p, ok
+}
+
+// normalizeName returns name in lower case without a "crc-16/" or "crc16/"
+// prefix, hyphens and underscores.
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	for _, prefix := range []string{"crc-16/", "crc16/"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return strings.NewReplacer("-", "", "_", "").Replace(name)
+}
+
+// Residues of the catalog algorithms, as returned by ResidueOf.
+const (
+	ResidueARC         = 0x0000
+	ResidueModbus      = 0x0000
+	ResidueUSB         = 0x4ffe
+	ResidueMAXIM       = 0x4ffe
+	ResidueKERMIT      = 0x0000
+	ResidueX25         = 0x0f47
+	ResidueXMODEM      = 0x0000
+	ResidueCCITTFalse  = 0x0000
+	ResidueGENIBUS     = 0xe2f0
+	ResidueDNP         = 0x993a
+	ResidueT10DIF      = 0x0000
+	ResidueProfibus    = 0x1c6b
+	ResidueGSM         = 0xe2f0
+	ResidueMCRF4XX     = 0x0000
+	ResidueRIELLO      = 0x0000
+	ResidueCDMA2000    = 0x0000
+	ResidueTELEDISK    = 0x0000
+	ResidueOpenSafetyA = 0x0000
+	ResidueOpenSafetyB = 0x0000
+	ResidueLJ1200      = 0x0000
+	ResidueM17         = 0x0000
+	ResidueNRSC5       = 0x0000
+	ResidueDECTR       = 0x0588
+	ResidueDECTX       = 0x0000
+)
+
+// XMODEMTable is the non-reflected table for the polynomial 0x1021, used
+// by XMODEM, CCITT-FALSE, GENIBUS and GSM with UpdateMSB.
+var XMODEMTable = TableFor(XMODEM)
+
+// Tables shared by the catalog algorithms, built on first use.
+var (
+	reflected8005Table = sync.OnceValue(func() *Table { return TableFor(ARC) })
+	reflected3D65Table = sync.OnceValue(func() *Table { return TableFor(DNP) })
+	normal8BB7Table    = sync.OnceValue(func() *Table { return TableFor(T10DIF) })
+	normal1DCFTable    = sync.OnceValue(func() *Table { return TableFor(Profibus) })
+	normalC867Table    = sync.OnceValue(func() *Table { return TableFor(CDMA2000) })
+	normalA097Table    = sync.OnceValue(func() *Table { return TableFor(TELEDISK) })
+	normal5935Table    = sync.OnceValue(func() *Table { return TableFor(OpenSafetyA) })
+	normal755BTable    = sync.OnceValue(func() *Table { return TableFor(OpenSafetyB) })
+	normal6F63Table    = sync.OnceValue(func() *Table { return TableFor(LJ1200) })
+	reflected080BTable = sync.OnceValue(func() *Table { return TableFor(NRSC5) })
+	normal0589Table    = sync.OnceValue(func() *Table { return TableFor(DECTR) })
+)
+
+// NewARC creates a new Hash16 computing the CRC-16/ARC checksum.
+func NewARC() Hash16 { return NewParams(ARC) }
+
+// ChecksumARC returns the CRC-16/ARC checksum of data.
+func ChecksumARC(data []byte) uint16 { return ARC.checksum(reflected8005Table(), data) }
+
+// NewModbus creates a new Hash16 computing the CRC-16/MODBUS checksum.
+func NewModbus() Hash16 { return NewParams(Modbus) }
+
+// ChecksumModbus returns the CRC-16/MODBUS checksum of data.
+func ChecksumModbus(data []byte) uint16 { return Modbus.checksum(reflected8005Table(), data) }
+
+// AppendModbus appends the CRC-16/MODBUS checksum of frame to frame in the
+// little-endian order of Modbus RTU and returns the extended slice.
+func AppendModbus(frame []byte) []byte {
+	crc := ChecksumModbus(frame)
+	return append(frame, byte(crc), byte(crc>>8))
+}
+
+// NewUSB creates a new Hash16 computing the CRC-16/USB checksum.
+func NewUSB() Hash16 { return NewParams(USB) }
+
+// ChecksumUSB returns the CRC-16/USB checksum of data.
+func ChecksumUSB(data []byte) uint16 { return USB.checksum(reflected8005Table(), data) }
+
+// ChecksumMAXIM returns the CRC-16/MAXIM-DOW checksum of data.
+func ChecksumMAXIM(data []byte) uint16 { return MAXIM.checksum(reflected8005Table(), data) }
+
+// NewKERMIT creates a new Hash16 computing the CRC-16/KERMIT checksum.
+func NewKERMIT() Hash16 { return NewParams(KERMIT) }
+
+// ChecksumKERMIT returns the CRC-16/KERMIT checksum of data.
+func ChecksumKERMIT(data []byte) uint16 { return KERMIT.checksum(CCITTTable, data) }
+
+// AppendKERMIT appends the CRC-16/KERMIT checksum of frame to frame in
+// little-endian order, as transmitted, and returns the extended slice.
+func AppendKERMIT(frame []byte) []byte {
+	crc := ChecksumKERMIT(frame)
+	return append(frame, byte(crc), byte(crc>>8))
+}
+
+// ChecksumX25 returns the CRC-16/IBM-SDLC checksum of data.
+func ChecksumX25(data []byte) uint16 { return X25.checksum(CCITTTable, data) }
+
+// NewXMODEM creates a new Hash16 computing the CRC-16/XMODEM checksum.
+func NewXMODEM() Hash16 { return NewParams(XMODEM) }
+
+// ChecksumXMODEM returns the CRC-16/XMODEM checksum of data.
+func ChecksumXMODEM(data []byte) uint16 { return XMODEM.checksum(XMODEMTable, data) }
+
+// NewCCITTFalse creates a new Hash16 computing the CRC-16/CCITT-FALSE
+// checksum.
+func NewCCITTFalse() Hash16 { return NewParams(CCITTFalse) }
+
+// ChecksumCCITTFalse returns the CRC-16/CCITT-FALSE checksum of data.
+func ChecksumCCITTFalse(data []byte) uint16 { return CCITTFalse.checksum(XMODEMTable, data) }
+
+// ChecksumGENIBUS returns the CRC-16/GENIBUS checksum of data.
+func ChecksumGENIBUS(data []byte) uint16 { return GENIBUS.checksum(XMODEMTable, data) }
+
+// NewDNP creates a new Hash16 computing the CRC-16/DNP checksum.
+func NewDNP() Hash16 { return NewParams(DNP) }
+
+// ChecksumDNP returns the CRC-16/DNP checksum of data.
+func ChecksumDNP(data []byte) uint16 { return DNP.checksum(reflected3D65Table(), data) }
+
+// NewT10DIF creates a new Hash16 computing the CRC-16/T10-DIF checksum.
+func NewT10DIF() Hash16 { return NewParams(T10DIF) }
+
+// ChecksumT10DIF returns the CRC-16/T10-DIF checksum of data.
+func ChecksumT10DIF(data []byte) uint16 { return T10DIF.checksum(normal8BB7Table(), data) }
+
+// NewProfibus creates a new Hash16 computing the CRC-16/PROFIBUS checksum.
+func NewProfibus() Hash16 { return NewParams(Profibus) }
+
+// ChecksumProfibus returns the CRC-16/PROFIBUS checksum of data.
+func ChecksumProfibus(data []byte) uint16 { return Profibus.checksum(normal1DCFTable(), data) }
+
+// AppendProfibus appends the CRC-16/PROFIBUS checksum of frame to frame in
+// big-endian order and returns the extended slice. The result is accepted
+// by VerifyResidueParams with Profibus.
+func AppendProfibus(frame []byte) []byte {
+	crc := ChecksumProfibus(frame)
+	return append(frame, byte(crc>>8), byte(crc))
+}
+
+// NewGSM creates a new Hash16 computing the CRC-16/GSM checksum.
+func NewGSM() Hash16 { return NewParams(GSM) }
+
+// ChecksumGSM returns the CRC-16/GSM checksum of data.
+func ChecksumGSM(data []byte) uint16 { return GSM.checksum(XMODEMTable, data) }
+
+// NewMCRF4XX creates a new Hash16 computing the CRC-16/MCRF4XX checksum.
+func NewMCRF4XX() Hash16 { return NewParams(MCRF4XX) }
+
+// ChecksumMCRF4XX returns the CRC-16/MCRF4XX checksum of data.
+func ChecksumMCRF4XX(data []byte) uint16 { return MCRF4XX.checksum(CCITTTable, data) }
+
+// NewRIELLO creates a new Hash16 computing the CRC-16/RIELLO checksum.
+func NewRIELLO() Hash16 { return NewParams(RIELLO) }
+
+// ChecksumRIELLO returns the CRC-16/RIELLO checksum of data.
+func ChecksumRIELLO(data []byte) uint16 { return RIELLO.checksum(CCITTTable, data) }
+
+// NewCDMA2000 creates a new Hash16 computing the CRC-16/CDMA2000 checksum.
+func NewCDMA2000() Hash16 { return NewParams(CDMA2000) }
+
+// ChecksumCDMA2000 returns the CRC-16/CDMA2000 checksum of data.
+func ChecksumCDMA2000(data []byte) uint16 { return CDMA2000.checksum(normalC867Table(), data) }
+
+// NewTELEDISK creates a new Hash16 computing the CRC-16/TELEDISK checksum.
+func NewTELEDISK() Hash16 { return NewParams(TELEDISK) }
+
+// ChecksumTELEDISK returns the CRC-16/TELEDISK checksum of data.
+func ChecksumTELEDISK(data []byte) uint16 { return TELEDISK.checksum(normalA097Table(), data) }
+
+// NewOpenSafetyA creates a new Hash16 computing the CRC-16/OPENSAFETY-A
+// checksum.
+func NewOpenSafetyA() Hash16 { return NewParams(OpenSafetyA) }
+
+// ChecksumOpenSafetyA returns the CRC-16/OPENSAFETY-A checksum of data.
+func ChecksumOpenSafetyA(data []byte) uint16 { return OpenSafetyA.checksum(normal5935Table(), data) }
+
+// NewOpenSafetyB creates a new Hash16 computing the CRC-16/OPENSAFETY-B
+// checksum.
+func NewOpenSafetyB() Hash16 { return NewParams(OpenSafetyB) }
+
+// ChecksumOpenSafetyB returns the CRC-16/OPENSAFETY-B checksum of data.
+func ChecksumOpenSafetyB(data []byte) uint16 { return OpenSafetyB.checksum(normal755BTable(), data) }
+
+// NewLJ1200 creates a new Hash16 computing the CRC-16/LJ1200 checksum.
+func NewLJ1200() Hash16 { return NewParams(LJ1200) }
+
+// ChecksumLJ1200 returns the CRC-16/LJ1200 checksum of data.
+func ChecksumLJ1200(data []byte) uint16 { return LJ1200.checksum(normal6F63Table(), data) }
+
+// NewM17 creates a new Hash16 computing the CRC-16/M17 checksum.
+func NewM17() Hash16 { return NewParams(M17) }
+
+// ChecksumM17 returns the CRC-16/M17 checksum of data.
+func ChecksumM17(data []byte) uint16 { return M17.checksum(normal5935Table(), data) }
+
+// NewNRSC5 creates a new Hash16 computing the CRC-16/NRSC-5 checksum.
+func NewNRSC5() Hash16 { return NewParams(NRSC5) }
+
+// ChecksumNRSC5 returns the CRC-16/NRSC-5 checksum of data.
+func ChecksumNRSC5(data []byte) uint16 { return NRSC5.checksum(reflected080BTable(), data) }
+
+// NewDECTR creates a new Hash16 computing the CRC-16/DECT-R checksum.
+func NewDECTR() Hash16 { return NewParams(DECTR) }
+
+// ChecksumDECTR returns the CRC-16/DECT-R checksum of data.
+func ChecksumDECTR(data []byte) uint16 { return DECTR.checksum(normal0589Table(), data) }
+
+// NewDECTX creates a new Hash16 computing the CRC-16/DECT-X checksum.
+func NewDECTX() Hash16 { return NewParams(DECTX) }
+
+// ChecksumDECTX returns the CRC-16/DECT-X checksum of data.
+func ChecksumDECTX(data []byte) uint16 { return DECTX.checksum(normal0589Table(), data) }
@@ -0,0 +1,386 @@
+package crc16
+
+import (
+	"testing"
+)
+
+var checkData = []byte("123456789")
+
+// catalogTests lists the catalog algorithms with their check values from
+// the CRC RevEng catalogue.
+var catalogTests = []struct {
+	name     string
+	p        Params
+	checksum func([]byte) uint16
+	check    uint16
+}{
+	{"ARC", ARC, ChecksumARC, 0xbb3d},
+	{"MODBUS", Modbus, ChecksumModbus, 0x4b37},
+	{"USB", USB, ChecksumUSB, 0xb4c8},
+	{"MAXIM-DOW", MAXIM, ChecksumMAXIM, 0x44c2},
+	{"KERMIT", KERMIT, ChecksumKERMIT, 0x2189},
+	{"IBM-SDLC", X25, ChecksumX25, 0x906e},
+	{"XMODEM", XMODEM, ChecksumXMODEM, 0x31c3},
+	{"CCITT-FALSE", CCITTFalse, ChecksumCCITTFalse, 0x29b1},
+	{"GENIBUS", GENIBUS, ChecksumGENIBUS, 0xd64e},
+	{"DNP", DNP, ChecksumDNP, 0xea82},
+	{"T10-DIF", T10DIF, ChecksumT10DIF, 0xd0db},
+	{"PROFIBUS", Profibus, ChecksumProfibus, 0xa819},
+	{"GSM", GSM, ChecksumGSM, 0xce3c},
+	{"MCRF4XX", MCRF4XX, ChecksumMCRF4XX, 0x6f91},
+	{"RIELLO", RIELLO, ChecksumRIELLO, 0x63d0},
+	{"CDMA2000", CDMA2000, ChecksumCDMA2000, 0x4c06},
+	{"TELEDISK", TELEDISK, ChecksumTELEDISK, 0x0fb3},
+	{"OPENSAFETY-A", OpenSafetyA, ChecksumOpenSafetyA, 0x5d38},
+	{"OPENSAFETY-B", OpenSafetyB, ChecksumOpenSafetyB, 0x20fe},
+	{"LJ1200", LJ1200, ChecksumLJ1200, 0xbdf4},
+	{"M17", M17, ChecksumM17, 0x772b},
+	{"NRSC-5", NRSC5, ChecksumNRSC5, 0xa066},
+	{"DECT-R", DECTR, ChecksumDECTR, 0x007e},
+	{"DECT-X", DECTX, ChecksumDECTX, 0x007f},
+}
+
+func TestCatalog(t *testing.T) {
+	for _, tt := range catalogTests {
+		if got := tt.checksum(checkData); got != tt.check {
+			t.Errorf("%s: checksum %#04x, want %#04x", tt.name, got, tt.check)
+		}
+
+		h := NewParams(tt.p)
+		h.Write(checkData)
+		if got := h.Sum16(); got != tt.check {
+			t.Errorf("%s: NewParams sum %#04x, want %#04x", tt.name, got, tt.check)
+		}
+		if got := tt.p.Check(); got != tt.check {
+			t.Errorf("%s: Check %#04x, want %#04x", tt.name, got, tt.check)
+		}
+	}
+}
+
+func TestResidueOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Params
+		residue uint16
+	}{
+		{"ARC", ARC, ResidueARC},
+		{"MODBUS", Modbus, ResidueModbus},
+		{"USB", USB, ResidueUSB},
+		{"MAXIM-DOW", MAXIM, ResidueMAXIM},
+		{"KERMIT", KERMIT, ResidueKERMIT},
+		{"IBM-SDLC", X25, ResidueX25},
+		{"XMODEM", XMODEM, ResidueXMODEM},
+		{"CCITT-FALSE", CCITTFalse, ResidueCCITTFalse},
+		{"GENIBUS", GENIBUS, ResidueGENIBUS},
+		{"DNP", DNP, ResidueDNP},
+		{"T10-DIF", T10DIF, ResidueT10DIF},
+		{"PROFIBUS", Profibus, ResidueProfibus},
+		{"GSM", GSM, ResidueGSM},
+		{"MCRF4XX", MCRF4XX, ResidueMCRF4XX},
+		{"RIELLO", RIELLO, ResidueRIELLO},
+		{"CDMA2000", CDMA2000, ResidueCDMA2000},
+		{"TELEDISK", TELEDISK, ResidueTELEDISK},
+		{"OPENSAFETY-A", OpenSafetyA, ResidueOpenSafetyA},
+		{"OPENSAFETY-B", OpenSafetyB, ResidueOpenSafetyB},
+		{"LJ1200", LJ1200, ResidueLJ1200},
+		{"M17", M17, ResidueM17},
+		{"NRSC-5", NRSC5, ResidueNRSC5},
+		{"DECT-R", DECTR, ResidueDECTR},
+		{"DECT-X", DECTX, ResidueDECTX},
+	}
+	for _, tt := range tests {
+		if got := ResidueOf(tt.p); got != tt.residue {
+			t.Errorf("%s: ResidueOf %#04x, want %#04x", tt.name, got, tt.residue)
+		}
+
+		// A correctly terminated frame yields the residue.
+		h := NewParams(tt.p)
+		h.Write([]byte("hello world"))
+		var frame []byte
+		if tt.p.RefIn {
+			frame = h.(*digest).SumLE([]byte("hello world"))
+		} else {
+			frame = h.Sum([]byte("hello world"))
+		}
+		h.Reset()
+		h.Write(frame)
+		if got := h.Sum16(); got != tt.residue {
+			t.Errorf("%s: frame checksum %#04x, want %#04x", tt.name, got, tt.residue)
+		}
+	}
+}
+
+func TestXMODEM(t *testing.T) {
+	if got := ChecksumXMODEM(checkData); got != 0x31c3 {
+		t.Errorf("ChecksumXMODEM: got %#04x, want 0x31c3", got)
+	}
+	h := NewXMODEM()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x31c3 {
+		t.Errorf("NewXMODEM: got %#04x, want 0x31c3", got)
+	}
+	if got := UpdateMSB(0, XMODEMTable, checkData); got != 0x31c3 {
+		t.Errorf("XMODEMTable: got %#04x, want 0x31c3", got)
+	}
+	if XMODEMTable != MakeTableRef(0x1021, false) {
+		t.Error("XMODEMTable is not the cached non-reflected 0x1021 table")
+	}
+}
+
+func TestTableFor(t *testing.T) {
+	if TableFor(Profibus) != TableFor(Profibus) {
+		t.Error("TableFor returned a new Table for the same Params")
+	}
+	if TableFor(KERMIT) != CCITTTable || TableFor(XMODEM) != XMODEMTable {
+		t.Error("TableFor did not return the shared tables")
+	}
+	if *TableFor(KERMIT) == *TableFor(XMODEM) {
+		t.Error("reflected and non-reflected tables are equal")
+	}
+}
+
+func TestModbus(t *testing.T) {
+	if got := ChecksumModbus(checkData); got != 0x4b37 {
+		t.Errorf("ChecksumModbus: got %#04x, want 0x4b37", got)
+	}
+	h := NewModbus()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x4b37 {
+		t.Errorf("NewModbus: got %#04x, want 0x4b37", got)
+	}
+
+	// Modbus RTU requests, including the example from the Modbus over
+	// serial line specification.
+	frames := []string{
+		"\x01\x03\x00\x00\x00\x0a\xc5\xcd",
+		"\x01\x04\x00\x00\x00\x01\x31\xca",
+		"\x11\x03\x00\x6b\x00\x03\x76\x87",
+	}
+	for _, frame := range frames {
+		pdu := []byte(frame[:len(frame)-2])
+		if got := AppendModbus(pdu); string(got) != frame {
+			t.Errorf("AppendModbus(% x) = % x, want % x", pdu, got, frame)
+		}
+	}
+}
+
+func TestDNP(t *testing.T) {
+	if got := ChecksumDNP(checkData); got != 0xea82 {
+		t.Errorf("ChecksumDNP: got %#04x, want 0xea82", got)
+	}
+	// The final XOR is applied once, to the register accumulated over
+	// all writes.
+	h := NewDNP()
+	h.Write(checkData[:3])
+	h.Write(checkData[3:])
+	if got := h.Sum16(); got != 0xea82 {
+		t.Errorf("NewDNP: got %#04x, want 0xea82", got)
+	}
+	if got := h.Sum16(); got != 0xea82 {
+		t.Errorf("NewDNP: got %#04x on second Sum16, want 0xea82", got)
+	}
+}
+
+func TestUSB(t *testing.T) {
+	if got := ChecksumUSB(checkData); got != 0xb4c8 {
+		t.Errorf("ChecksumUSB: got %#04x, want 0xb4c8", got)
+	}
+	h := NewUSB()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0xb4c8 {
+		t.Errorf("NewUSB: got %#04x, want 0xb4c8", got)
+	}
+	if ChecksumUSB(checkData) == ChecksumANSI(checkData) {
+		t.Error("ChecksumUSB matches ChecksumANSI")
+	}
+}
+
+func TestCCITTFalse(t *testing.T) {
+	if got := ChecksumCCITTFalse(checkData); got != 0x29b1 {
+		t.Errorf("ChecksumCCITTFalse: got %#04x, want 0x29b1", got)
+	}
+	h := NewCCITTFalse()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x29b1 {
+		t.Errorf("NewCCITTFalse: got %#04x, want 0x29b1", got)
+	}
+	// CCITT-FALSE shares the polynomial of CCITTTable but processes bits
+	// most significant first without a final XOR, while ChecksumCCITT is
+	// the reflected X-25 algorithm.
+	if got := ChecksumCCITT(checkData); got != 0x906e {
+		t.Errorf("ChecksumCCITT: got %#04x, want 0x906e", got)
+	}
+}
+
+func TestT10DIF(t *testing.T) {
+	h := NewT10DIF()
+	h.Write(checkData[:5])
+	h.Write(checkData[5:])
+	if got := h.Sum16(); got != 0xd0db {
+		t.Errorf("NewT10DIF: got %#04x, want 0xd0db", got)
+	}
+}
+
+func TestProfibus(t *testing.T) {
+	// Both the initial value and the final XOR are 0xFFFF; the checksum
+	// must apply each exactly once.
+	h := NewProfibus()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0xa819 {
+		t.Errorf("NewProfibus: got %#04x, want 0xa819", got)
+	}
+	if got := ChecksumProfibus(nil); got != 0 {
+		t.Errorf("empty message: got %#04x, want 0", got)
+	}
+
+	frame := AppendProfibus([]byte("123456789"))
+	if string(frame) != "123456789\xa8\x19" {
+		t.Errorf("AppendProfibus: got % x", frame)
+	}
+	if !VerifyResidueParams(frame, Profibus) {
+		t.Error("VerifyResidueParams rejected the frame")
+	}
+	for i := range frame {
+		frame[i] ^= 0x10
+		if VerifyResidueParams(frame, Profibus) {
+			t.Errorf("VerifyResidueParams accepted a frame with byte %d corrupted", i)
+		}
+		frame[i] ^= 0x10
+	}
+}
+
+func TestDECT(t *testing.T) {
+	// DECT-R and DECT-X differ only in the final XOR of 0x0001.
+	for _, data := range [][]byte{nil, {0}, checkData} {
+		if r, x := ChecksumDECTR(data), ChecksumDECTX(data); r != x^1 {
+			t.Errorf("% x: DECT-R %#04x, DECT-X %#04x", data, r, x)
+		}
+	}
+	h := NewDECTR()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x007e {
+		t.Errorf("NewDECTR: got %#04x, want 0x007e", got)
+	}
+}
+
+func TestARC(t *testing.T) {
+	if got := ChecksumARC(checkData); got != 0xbb3d {
+		t.Errorf("ChecksumARC: got %#04x, want 0xbb3d", got)
+	}
+	h := NewARC()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0xbb3d {
+		t.Errorf("NewARC: got %#04x, want 0xbb3d", got)
+	}
+	// ARC differs from MODBUS only by its initial value of zero.
+	if got := ChecksumWith(checkData, reflected8005Table(), 0, 0); got != 0xbb3d {
+		t.Errorf("ChecksumWith: got %#04x, want 0xbb3d", got)
+	}
+}
+
+func TestGSM(t *testing.T) {
+	h := NewGSM()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0xce3c {
+		t.Errorf("NewGSM: got %#04x, want 0xce3c", got)
+	}
+	for _, data := range [][]byte{nil, {0}, checkData, []byte("hello world")} {
+		if got, want := ChecksumGSM(data), ChecksumXMODEM(data)^0xffff; got != want {
+			t.Errorf("%q: got %#04x, want XMODEM^0xffff = %#04x", data, got, want)
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	tests := []struct {
+		name string
+		want Params
+	}{
+		{"modbus", Modbus},
+		{"MODBUS", Modbus},
+		{"XModem", XMODEM},
+		{"kermit", KERMIT},
+		{"ccitt-false", CCITTFalse},
+		{"CRC-16/IBM-3740", CCITTFalse},
+		{"crc16/x-25", X25},
+		{"IBM-SDLC", X25},
+		{"t10_dif", T10DIF},
+	}
+	for _, tt := range tests {
+		if got, ok := ByName(tt.name); !ok || got != tt.want {
+			t.Errorf("ByName(%q) = %+v, %v; want %+v", tt.name, got, ok, tt.want)
+		}
+	}
+
+	// Every catalog entry can be found by its own name.
+	for _, tt := range catalogTests {
+		if got, ok := ByName(tt.p.Name); !ok || got != tt.p {
+			t.Errorf("ByName(%q) = %+v, %v", tt.p.Name, got, ok)
+		}
+	}
+
+	for _, name := range []string{"", "crc-16/", "sha256", "modbuss"} {
+		if _, ok := ByName(name); ok {
+			t.Errorf("ByName(%q) succeeded", name)
+		}
+	}
+}
+
+func TestKERMIT(t *testing.T) {
+	h := NewKERMIT()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x2189 {
+		t.Errorf("NewKERMIT: got %#04x, want 0x2189", got)
+	}
+
+	frame := AppendKERMIT([]byte("123456789"))
+	if string(frame) != "123456789\x89\x21" {
+		t.Errorf("AppendKERMIT: got % x", frame)
+	}
+	// Verify checks the X-25 algorithm of CCITTTable; a KERMIT frame is
+	// checked through its residue instead.
+	if got := ChecksumKERMIT(frame); got != ResidueKERMIT {
+		t.Errorf("frame checksum %#04x, want residue %#04x", got, ResidueKERMIT)
+	}
+	frame[0] ^= 1
+	if got := ChecksumKERMIT(frame); got == ResidueKERMIT {
+		t.Error("corrupted frame has the residue")
+	}
+}
+
+func TestReflectedInit(t *testing.T) {
+	// Both algorithms start from an initial value that must be reflected
+	// before use; 0xB2AA is not symmetric under reflection.
+	for _, tt := range []struct {
+		name  string
+		h     Hash16
+		check uint16
+	}{
+		{"MCRF4XX", NewMCRF4XX(), 0x6f91},
+		{"RIELLO", NewRIELLO(), 0x63d0},
+	} {
+		tt.h.Write(checkData[:4])
+		tt.h.Write(checkData[4:])
+		if got := tt.h.Sum16(); got != tt.check {
+			t.Errorf("%s: got %#04x, want %#04x", tt.name, got, tt.check)
+		}
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	// A Modbus RTU request and its little-endian checksum.
+	frame := []byte("\x01\x03\x00\x00\x00\x0a\xc5\xcd")
+	if got := Identify(frame[:6], 0xcdc5); len(got) != 1 || got[0] != "MODBUS" {
+		t.Errorf("Modbus frame: got %q, want [MODBUS]", got)
+	}
+
+	for _, tt := range catalogTests {
+		if got := Identify(checkData, tt.check); len(got) != 1 || got[0] != tt.p.Name {
+			t.Errorf("%s: got %q", tt.name, got)
+		}
+	}
+
+	if got := Identify(checkData, 0x1234); got != nil {
+		t.Errorf("unknown checksum: got %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"runtime"
+	"sync"
+)
+
+// gf2Matrix is a 16x16 matrix over GF(2). Element i is the column
+// holding the image of the vector with only bit i set.
+type gf2Matrix [16]uint16
+
+// times returns the product of m and the vector v.
+func (m *gf2Matrix) times(v uint16) uint16 {
+	var r uint16
+	for i := 0; v != 0; i, v = i+1, v>>1 {
+		if v&1 != 0 {
+			r ^= m[i]
+		}
+	}
+	return r
+}
+
+// square returns the product of m and itself.
+func (m *gf2Matrix) square() gf2Matrix {
+	var r gf2Matrix
+	for i := range r {
+		r[i] = m.times(m[i])
+	}
+	return r
+}
+
+// shift returns the shift register crc advanced over n zero bytes, using
+// tab with the reflected algorithm if reflected is set and the
+// non-reflected algorithm otherwise. The work is logarithmic in n.
+func shift(crc uint16, n int64, tab *Table, reflected bool) uint16 {
+	// Build the operator for a single zero byte, then raise it to the
+	// n-th power by repeated squaring.
+	var m gf2Matrix
+	for i := range m {
+		v := uint16(1) << uint(i)
+		if reflected {
+			m[i] = tab[byte(v)] ^ (v >> 8)
+		} else {
+			m[i] = tab[byte(v>>8)] ^ (v << 8)
+		}
+	}
+	for n > 0 {
+		if n&1 != 0 {
+			crc = m.times(crc)
+		}
+		if n >>= 1; n > 0 {
+			m = m.square()
+		}
+	}
+	return crc
+}
+
+// ShiftZeros returns the result of adding n zero bytes to the crc, as
+// Update(crc, tab, make([]byte, n)) does, in time logarithmic in n rather
+// than linear. ShiftZeros returns crc if n is zero or negative.
+func ShiftZeros(crc uint16, n int64, tab *Table) uint16 {
+	if n <= 0 {
+		return crc
+	}
+	return ^shift(^crc, n, tab, true)
+}
+
+// Combine returns the CRC-16 checksum of the concatenation of two byte
+// sequences A and B, given crc1, the Checksum of A, crc2, the Checksum of
+// B, and len2, the length of B, all computed using the same Table.
+func Combine(crc1, crc2 uint16, len2 int64, tab *Table) uint16 {
+	if len2 <= 0 {
+		return crc1
+	}
+	// The complements applied by Update cancel out, so only crc1 has to
+	// be carried over the bytes of B.
+	return shift(crc1, len2, tab, true) ^ crc2
+}
+
+// FoldIn updates the digest as if otherLen bytes were written whose
+// checksum, computed by a digest with the same parameters, is other. It
+// lets the running checksums of consecutive shards be merged without
+// reading the data again. FoldIn does nothing if otherLen is zero or
+// negative.
+func (d *digest) FoldIn(other uint16, otherLen int64) {
+	if otherLen <= 0 {
+		return
+	}
+	// Undo the finalization to recover the shift register of the other
+	// bytes. Its contribution from the initial value is cancelled by
+	// carrying the initial value over those bytes along with d.crc.
+	p := &d.params
+	crc := other ^ p.XorOut
+	if p.RefIn != p.RefOut {
+		crc = Reverse16(crc)
+	}
+	d.crc = shift(d.crc^p.register(), otherLen, d.tab, p.RefIn) ^ crc
+	d.n += otherLen
+}
+
+// ChecksumRepeat returns the Checksum, using the Table, of n copies of the
+// byte b, without allocating them. The work is logarithmic in n.
+func ChecksumRepeat(b byte, n int64, tab *Table) uint16 {
+	var crc uint16
+	block, size := Checksum([]byte{b}, tab), int64(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			crc = Combine(crc, block, size, tab)
+		}
+		if n > 1 {
+			block = Combine(block, block, size, tab)
+			size *= 2
+		}
+	}
+	return crc
+}
+
+// XorChecksum returns the Checksum of the bytewise XOR of two messages of
+// the given length, given crcA and crcB, their Checksums using the Table.
+// Since CRCs are affine over GF(2), it is crcA ^ crcB ^ Checksum(z), where
+// z is length zero bytes; the last term cancels the initial value and
+// final XOR counted twice.
+func XorChecksum(crcA, crcB uint16, length int, tab *Table) uint16 {
+	return crcA ^ crcB ^ ShiftZeros(0, int64(length), tab)
+}
+
+// parallelMinChunk is the smallest amount of data that ChecksumParallel
+// hands to a goroutine.
+const parallelMinChunk = 64 << 10
+
+// ChecksumParallel returns the Checksum of data using the Table, splitting
+// data into up to workers chunks that are checksummed concurrently and
+// merged with Combine. If workers is zero or negative, GOMAXPROCS is used.
+// Inputs too small to benefit are checksummed serially.
+func ChecksumParallel(data []byte, tab *Table, workers int) uint16 {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n := len(data) / parallelMinChunk; workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		return Checksum(data, tab)
+	}
+
+	size := (len(data) + workers - 1) / workers
+	sums := make([]uint16, workers)
+	var wg sync.WaitGroup
+	for i := range sums {
+		chunk := data[i*size : min((i+1)*size, len(data))]
+		wg.Add(1)
+		go func(i int, chunk []byte) {
+			defer wg.Done()
+			sums[i] = Checksum(chunk, tab)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	crc := sums[0]
+	for i := 1; i < workers; i++ {
+		n := min((i+1)*size, len(data)) - i*size
+		crc = Combine(crc, sums[i], int64(n), tab)
+	}
+	return crc
+}
@@ -0,0 +1,127 @@
+package crc16
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	data := make([]byte, 1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xA001)} {
+		want := Checksum(data, tab)
+		for _, split := range []int{0, 1, 2, 7, 100, 512, len(data) - 2, len(data) - 1, len(data)} {
+			a, b := data[:split], data[split:]
+			got := Combine(Checksum(a, tab), Checksum(b, tab), int64(len(b)), tab)
+			if got != want {
+				t.Errorf("split %d: got %#04x, want %#04x", split, got, want)
+			}
+		}
+	}
+}
+
+func TestShiftZeros(t *testing.T) {
+	zeros := make([]byte, 1<<20+3)
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001)} {
+		crc := Checksum(checkData, tab)
+		for _, n := range []int{0, 1, 2, 15, 16, 1000, 1 << 16, 1 << 20, len(zeros)} {
+			want := Update(crc, tab, zeros[:n])
+			if got := ShiftZeros(crc, int64(n), tab); got != want {
+				t.Errorf("%d zeros: got %#04x, want %#04x", n, got, want)
+			}
+		}
+	}
+}
+
+func TestChecksumRepeat(t *testing.T) {
+	for _, tab := range []*Table{ANSITable, CCITTTable} {
+		for _, b := range []byte{0x00, 0x5a, 0xff} {
+			for _, n := range []int{0, 1, 2, 3, 7, 8, 100, 1000, 1<<16 + 1} {
+				want := Checksum(bytes.Repeat([]byte{b}, n), tab)
+				if got := ChecksumRepeat(b, int64(n), tab); got != want {
+					t.Errorf("%d x %#02x: got %#04x, want %#04x", n, b, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestXorChecksum(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001)} {
+		for _, n := range []int{0, 1, 2, 16, 100, 4096} {
+			a, b, x := make([]byte, n), make([]byte, n), make([]byte, n)
+			rnd.Read(a)
+			rnd.Read(b)
+			for i := range x {
+				x[i] = a[i] ^ b[i]
+			}
+			got := XorChecksum(Checksum(a, tab), Checksum(b, tab), n, tab)
+			if want := Checksum(x, tab); got != want {
+				t.Errorf("%d bytes: got %#04x, want %#04x", n, got, want)
+			}
+		}
+	}
+}
+
+func TestFoldIn(t *testing.T) {
+	data := make([]byte, 1000)
+	rand.New(rand.NewSource(1)).Read(data)
+	mixed := Params{Name: "mixed", Poly: 0x1021, Init: 0x1d0f, RefIn: true, RefOut: false, XorOut: 0x5555}
+	factories := []Factory{NewANSI, NewCCITT, NewXMODEM, NewProfibus, FactoryParams(DECTR), FactoryParams(mixed)}
+	for _, f := range factories {
+		want := f()
+		want.Write(data)
+		for _, split := range []int{0, 1, 500, 999, 1000} {
+			shard1, shard2 := f(), f()
+			shard1.Write(data[:split])
+			shard2.Write(data[split:])
+			d := shard1.(*digest)
+			d.FoldIn(shard2.Sum16(), int64(len(data)-split))
+			if got := d.Sum16(); got != want.Sum16() {
+				t.Errorf("%s, split %d: got %#04x, want %#04x", d.Params().Name, split, got, want.Sum16())
+			}
+			if d.Written() != int64(len(data)) {
+				t.Errorf("%s, split %d: Written %d", d.Params().Name, split, d.Written())
+			}
+		}
+	}
+}
+
+func TestChecksumParallel(t *testing.T) {
+	data := make([]byte, 3<<20+17)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, tab := range []*Table{ANSITable, MakeTable(0xA001)} {
+		want := Checksum(data, tab)
+		for _, workers := range []int{0, 1, 2, 3, 7, 64} {
+			if got := ChecksumParallel(data, tab, workers); got != want {
+				t.Errorf("%d workers: got %#04x, want %#04x", workers, got, want)
+			}
+		}
+		small := data[:1000]
+		if got, want := ChecksumParallel(small, tab, 4), Checksum(small, tab); got != want {
+			t.Errorf("small input: got %#04x, want %#04x", got, want)
+		}
+	}
+}
+
+func BenchmarkChecksumParallel(b *testing.B) {
+	data := make([]byte, 16<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	b.Run("serial", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			sink = Checksum(data, ANSITable)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			sink = ChecksumParallel(data, ANSITable, 0)
+		}
+	})
+}
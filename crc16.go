@@ -8,26 +8,136 @@
 // information.
 package crc16
 
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
 // The size of a CRC-16 checksum in bytes.
 const Size = 2
 
 // https://en.wikipedia.org/wiki/Cyclic_redundancy_check#Standards_and_common_use
 const (
-	// Bisync, Modbus, USB, ANSI X3.28, SIA DC-07, many others
+	// ANSI is the ANSI polynomial 0x8005 in normal notation, a legacy
+	// value kept for compatibility. MakeTable(ANSI) treats 0x8005 as
+	// reversed notation, that is the normal polynomial 0xA001, so
+	// ANSITable computes none of the standard algorithms (check value
+	// 0xC284). For the CRC-16 of Modbus, USB and ANSI X3.28 use Modbus or
+	// ARC, or MakeTable(ReversedPoly(ANSI)), the reversed notation 0xA001.
 	ANSI = 0x8005
-	// X.25, V.41, HDLC FCS, XMODEM, Bluetooth, PACTOR, SD, many others
-	CCITT = 0x1021
+	// CCITT is the CCITT polynomial of X.25, V.41, HDLC FCS, Bluetooth and
+	// SD in reversed notation: 0x8408 is the normal polynomial 0x1021. It
+	// is only meant for the reflected algorithm of MakeTable and Update;
+	// XMODEM and the other non-reflected algorithms process the normal
+	// polynomial most significant bit first, as XMODEMTable does.
+	CCITT = 0x8408
 )
 
+// Table is a 256-word table representing the polynomial for efficient
+// processing. A Table that is not modified may be used by any number of
+// goroutines at once, as may the functions of this package that take one.
 type Table [256]uint16
 
-// ANSITable is the table for the ANSI polynomial.
+// ANSITable is the table for the legacy ANSI value, which MakeTable reads
+// in reversed notation; it does not compute CRC-16/ARC or Modbus (see
+// ANSI). It is shared by every user of the package and must not be
+// modified; use ANSITableCopy to obtain a Table that can be.
 var ANSITable = makeTable(ANSI)
 
-// CCITTTable is the table for the CCITT polynomial.
+// CCITTTable is the table for the CCITT polynomial. Used with Update and
+// Checksum it computes CRC-16/X-25 (check value 0x906E). Like ANSITable,
+// it must not be modified.
 var CCITTTable = makeTable(CCITT)
 
+// ANSITableCopy returns a new copy of ANSITable.
+func ANSITableCopy() *Table {
+	t := *ANSITable
+	return &t
+}
+
+// CCITTTableCopy returns a new copy of CCITTTable.
+func CCITTTableCopy() *Table {
+	t := *CCITTTable
+	return &t
+}
+
+// derived holds the tables of the faster algorithms for a reflected Table,
+// built on first use.
+type derived struct {
+	once     sync.Once
+	slicing8 *slicing8Table
+	arch     archTable // for archUpdate
+}
+
+// Derived tables of the built-in Tables and of the reflected Tables cached
+// by MakeTable, which are never modified. Tables built by callers may be
+// modified at any time and always use the simple algorithm. The map is
+// replaced rather than updated, so that lookups need no lock.
+var (
+	derivedANSI   derived
+	derivedCCITT  derived
+	derivedMu     sync.Mutex
+	derivedTables atomic.Pointer[map[*Table]*derived]
+)
+
+// sharedTable records the reflected Table tab, cached by the package, as
+// eligible for the faster algorithms.
+func sharedTable(tab *Table) {
+	derivedMu.Lock()
+	defer derivedMu.Unlock()
+	m := make(map[*Table]*derived)
+	if old := derivedTables.Load(); old != nil {
+		for k, v := range *old {
+			m[k] = v
+		}
+	}
+	m[tab] = new(derived)
+	derivedTables.Store(&m)
+}
+
+// derivedFor returns the derived tables of tab, or nil if tab was not
+// built by the package.
+func derivedFor(tab *Table) *derived {
+	var d *derived
+	switch tab {
+	case ANSITable:
+		d = &derivedANSI
+	case CCITTTable:
+		d = &derivedCCITT
+	default:
+		m := derivedTables.Load()
+		if m == nil {
+			return nil
+		}
+		if d = (*m)[tab]; d == nil {
+			return nil
+		}
+	}
+	d.once.Do(func() {
+		d.slicing8 = slicingMakeTable(tab)
+		d.arch = archMakeTable(tab)
+	})
+	return d
+}
+
+// tables caches the Tables built by MakeTable and MakeTableRef.
+var tables sync.Map // map[tableKey]*Table
+
+type tableKey struct {
+	poly      uint16
+	reflected bool
+}
+
 // MakeTable returns the Table constructed from the specified polynomial.
+// The polynomial is in reversed notation, least significant bit first:
+// 0x8408 rather than 0x1021 for CCITT (see ReversedPoly). Tables are
+// cached: every call with the same polynomial returns the same Table,
+// which must not be modified.
 func MakeTable(poly uint16) *Table {
 	switch poly {
 	case ANSI:
@@ -35,79 +145,528 @@ func MakeTable(poly uint16) *Table {
 	case CCITT:
 		return CCITTTable
 	}
-	return makeTable(poly)
+	return cachedTable(tableKey{poly, true})
+}
+
+// ErrZeroPoly is returned by MakeTableChecked for the zero polynomial.
+var ErrZeroPoly = errors.New("crc16: zero polynomial")
+
+// MakeTableChecked is like MakeTable but returns ErrZeroPoly instead of a
+// Table if poly is zero. The Table of the zero polynomial holds only
+// zeros, so every checksum computed with it is the same constant.
+func MakeTableChecked(poly uint16) (*Table, error) {
+	if poly == 0 {
+		return nil, ErrZeroPoly
+	}
+	return MakeTable(poly), nil
+}
+
+// MakeTableRef returns the Table constructed from the specified polynomial
+// for the reflected algorithm of Update if reflected is set, as MakeTable
+// does, or for the non-reflected algorithm of UpdateMSB otherwise. The
+// polynomial is the value XORed into the shift register: in reversed
+// notation (0x8408 for CCITT) for reflected tables and in normal notation
+// (0x1021) otherwise. Tables are cached as by MakeTable.
+func MakeTableRef(poly uint16, reflected bool) *Table {
+	if reflected {
+		return MakeTable(poly)
+	}
+	return cachedTable(tableKey{poly, false})
+}
+
+// cachedTable returns the cached Table for k, building it if needed.
+func cachedTable(k tableKey) *Table {
+	if t, ok := tables.Load(k); ok {
+		return t.(*Table)
+	}
+	var t *Table
+	if k.reflected {
+		t = makeTable(k.poly)
+	} else {
+		t = makeTableMSB(k.poly)
+	}
+	v, loaded := tables.LoadOrStore(k, t)
+	if !loaded && k.reflected {
+		sharedTable(t)
+	}
+	return v.(*Table)
 }
 
 // makeTable returns the Table constructed from the specified polynomial.
-func makeTable(poly uint16) *Table {
-	t := new(Table)
-	for i := 0; i < 256; i++ {
-		crc := uint16(i)
-		for j := 0; j < 8; j++ {
-			if crc&1 == 1 {
-				crc = (crc >> 1) ^ poly
-			} else {
-				crc >>= 1
-			}
+func makeTable(poly uint16) *Table { return (*Table)(simpleMakeTable(poly)) }
+
+// makeTableMSB returns the Table constructed from the specified polynomial
+// for the non-reflected (most significant bit first) algorithm.
+func makeTableMSB(poly uint16) *Table { return (*Table)(simpleMakeTableMSB(poly)) }
+
+// WriteC writes t to w as a C array definition named name, with eight
+// entries per line, for comparison with tables used in firmware.
+func (t *Table) WriteC(w io.Writer, name string) error {
+	b := fmt.Appendf(nil, "static const uint16_t %s[256] = {\n", name)
+	for i, v := range t {
+		if i%8 == 0 {
+			b = append(b, "    "...)
+		}
+		b = fmt.Appendf(b, "0x%04X,", v)
+		if i%8 == 7 {
+			b = append(b, '\n')
+		} else {
+			b = append(b, ' ')
 		}
-		t[i] = crc
 	}
-	return t
+	b = append(b, "};\n"...)
+	_, err := w.Write(b)
+	return err
+}
+
+// Slice returns a copy of the 256 entries of t, for storing a Table
+// elsewhere. TableFromSlice reconstructs it.
+func (t *Table) Slice() []uint16 {
+	return append([]uint16(nil), t[:]...)
+}
+
+// ErrTableLength is returned by TableFromSlice for slices that do not
+// hold exactly 256 entries.
+var ErrTableLength = errors.New("crc16: table length is not 256")
+
+// TableFromSlice returns a new Table holding the entries of s, as returned
+// by Table.Slice. It returns ErrTableLength if s does not have 256 entries.
+// The entries are not checked to form a valid Table.
+func TableFromSlice(s []uint16) (*Table, error) {
+	if len(s) != len(Table{}) {
+		return nil, ErrTableLength
+	}
+	t := new(Table)
+	copy(t[:], s)
+	return t, nil
 }
 
 // digest represents the partial evaluation of a checksum.
 type digest struct {
-	crc uint16
-	tab *Table
+	crc    uint16
+	tab    *Table
+	params Params
+	order  binary.ByteOrder // of Sum and SumInto; nil means big-endian
+	n      int64            // bytes written since Reset
 }
 
-// New creates a new Hash16 computing the CRC-32 checksum
+// New creates a new Hash16 computing the CRC-16 checksum
 // using the polynomial represented by the Table.
-func New(tab *Table) Hash16 { return &digest{0, tab} }
+func New(tab *Table) Hash16 {
+	d := &digest{tab: tab, params: tableParams(tab)}
+	d.Reset()
+	return d
+}
+
+// NewInit creates a new Hash16 computing the checksum of the reflected
+// algorithm of Update with the Table, but starting from init instead of
+// 0xFFFF and without the final complement. As in Params, init is given
+// before reflection. With MakeTable(0xA001) and an init of zero, it
+// computes CRC-16/ARC.
+func NewInit(tab *Table, init uint16) Hash16 {
+	p := tableParams(tab)
+	p.Name, p.Init, p.XorOut = "custom", init, 0
+	d := &digest{tab: tab, params: p}
+	d.Reset()
+	return d
+}
 
-// NewANSI creates a new Hash16 computing the CRC-32 checksum
+// NewWithOrder is like New but the digest appends the checksum in the
+// given byte order in Sum and SumInto. New uses binary.BigEndian.
+func NewWithOrder(tab *Table, order binary.ByteOrder) Hash16 {
+	d := New(tab).(*digest)
+	d.order = order
+	return d
+}
+
+// NewANSI creates a new Hash16 computing the CRC-16 checksum
 // using the ANSI polynomial.
 func NewANSI() Hash16 { return New(ANSITable) }
 
-// NewCCITT creates a new Hash16 computing the CRC-32 checksum
+// NewCCITT creates a new Hash16 computing the CRC-16 checksum
 // using the CCITT polynomial.
 func NewCCITT() Hash16 { return New(CCITTTable) }
 
+// A Factory returns a new Hash16 each time it is called. Digests returned
+// by a Factory are independent of each other.
+type Factory func() Hash16
+
+// FactoryFor returns a Factory creating digests as New(tab) does.
+func FactoryFor(tab *Table) Factory {
+	return func() Hash16 { return New(tab) }
+}
+
+// Params returns the parameters of the algorithm computed by the digest.
+// For digests created by New, they describe the reflected algorithm of
+// Update with the Table, so NewParams(d.Params()) computes the same
+// checksums.
+func (d *digest) Params() Params { return d.params }
+
+// Reflected reports whether the digest processes the bits of each byte
+// least significant first. It is true for digests created by New, which
+// use the reflected algorithm of Update. For NewParams it returns RefIn;
+// use Params to tell the models whose RefIn and RefOut differ.
+func (d *digest) Reflected() bool { return d.params.RefIn }
+
 func (d *digest) Size() int { return Size }
 
 func (d *digest) BlockSize() int { return 1 }
 
-func (d *digest) Reset() { d.crc = 0 }
+func (d *digest) Reset() { d.crc, d.n = d.params.register(), 0 }
+
+// Written returns the number of bytes added to the checksum since the
+// digest was created or last reset.
+func (d *digest) Written() int64 { return d.n }
+
+// add adds the bytes in p to the checksum.
+func (d *digest) add(p []byte) {
+	d.crc = d.params.update(d.crc, d.tab, p)
+	d.n += int64(len(p))
+}
+
+// ResetWith resets the digest to compute the checksum of New(tab), keeping
+// its byte order, so that pooled digests can change polynomial.
+func (d *digest) ResetWith(tab *Table) {
+	d.tab, d.params = tab, tableParams(tab)
+	d.Reset()
+}
+
+// ResetParams is like ResetWith for the algorithm of NewParams(p).
+func (d *digest) ResetParams(p Params) {
+	d.tab, d.params = TableFor(p), p
+	d.Reset()
+}
+
+// Clone returns an independent copy of the digest holding the same state.
+// It lets a common prefix be hashed once and continued with different
+// suffixes.
+func (d *digest) Clone() Hash16 {
+	c := *d
+	return &c
+}
 
 // Update returns the result of adding the bytes in p to the crc.
+//
+// Update implements the reflected algorithm, which processes each byte
+// least significant bit first, with an initial value and final XOR of
+// 0xFFFF; the Table must be built for it, as by MakeTable. Use UpdateMSB
+// for algorithms that process bytes most significant bit first.
 func Update(crc uint16, tab *Table, p []byte) uint16 {
+	return ^update(^crc, tab, p)
+}
+
+// UpdateMSB returns the result of adding the bytes in p to the crc using
+// the non-reflected algorithm, which processes each byte most significant
+// bit first, as XMODEM and CCITT-FALSE do. The Table must be built for it,
+// as by MakeTableRef with reflected unset.
+//
+// Unlike Update, UpdateMSB does not complement crc on entry and exit: crc
+// is the shift register itself. Seed it with the initial value of the
+// algorithm and apply the final XOR to the result.
+func UpdateMSB(crc uint16, tab *Table, p []byte) uint16 {
+	return updateMSB(crc, tab, p)
+}
+
+// UpdateBitwise returns the result of adding the bytes in p to the crc,
+// like Update with the Table for poly, without using a table. It needs no
+// memory beyond the registers, at the cost of eight steps per byte. The
+// steps are branch-free, so the running time depends only on len(p). The
+// polynomial is in the form accepted by MakeTable.
+func UpdateBitwise(crc, poly uint16, p []byte) uint16 {
 	crc = ^crc
 	for _, v := range p {
-		crc = tab[byte(crc)^v] ^ (crc >> 8)
+		crc ^= uint16(v)
+		for j := 0; j < 8; j++ {
+			crc = (crc >> 1) ^ (poly & -(crc & 1))
+		}
 	}
 	return ^crc
 }
 
+// UpdateBits returns the result of adding the first nbits bits of p to the
+// crc, using the reflected algorithm of Update. Whole bytes are processed
+// as by Update. If nbits is not a multiple of 8, the remaining nbits%8 bits
+// are the most significant bits of the following byte, processed most
+// significant bit first. UpdateBits panics if nbits is negative or exceeds
+// 8*len(p).
+func UpdateBits(crc uint16, tab *Table, p []byte, nbits int) uint16 {
+	if nbits < 0 || nbits > 8*len(p) {
+		panic("crc16: UpdateBits bit count out of range")
+	}
+	n := nbits / 8
+	crc = update(^crc, tab, p[:n])
+	if k := nbits % 8; k > 0 {
+		poly := tab[0x80]
+		v := p[n]
+		for i := 0; i < k; i++ {
+			if (crc^uint16(v>>7))&1 != 0 {
+				crc = (crc >> 1) ^ poly
+			} else {
+				crc >>= 1
+			}
+			v <<= 1
+		}
+	}
+	return ^crc
+}
+
+// archCutoff is the smallest input handed to archUpdate, below which the
+// table-driven algorithms are faster.
+const archCutoff = 128
+
+// update returns the result of adding the bytes in p to the shift register
+// crc of the reflected algorithm.
+func update(crc uint16, tab *Table, p []byte) uint16 {
+	if len(p) >= slicing8Cutoff {
+		if d := derivedFor(tab); d != nil {
+			if len(p) >= archCutoff && archAvailable() {
+				return archUpdate(crc, d, p)
+			}
+			return slicingUpdate(crc, d.slicing8, p)
+		}
+	}
+	return simpleUpdate(crc, tab, p)
+}
+
+// updateMSB returns the result of adding the bytes in p to the shift
+// register crc of the non-reflected algorithm.
+func updateMSB(crc uint16, tab *Table, p []byte) uint16 {
+	return simpleUpdateMSB(crc, tab, p)
+}
+
 func (d *digest) Write(p []byte) (n int, err error) {
-	d.crc = Update(d.crc, d.tab, p)
+	d.add(p)
 	return len(p), nil
 }
 
-func (d *digest) Sum16() uint16 { return d.crc }
+// WriteString adds the bytes of s to the checksum without copying them.
+// It implements io.StringWriter.
+func (d *digest) WriteString(s string) (n int, err error) {
+	p := unsafe.Slice(unsafe.StringData(s), len(s))
+	d.add(p)
+	return len(s), nil
+}
+
+// WriteByte adds c to the checksum. It implements io.ByteWriter.
+func (d *digest) WriteByte(c byte) error {
+	p := [1]byte{c}
+	d.add(p[:])
+	return nil
+}
+
+// WriteUint16 adds v, encoded in the byte order order, to the checksum.
+func (d *digest) WriteUint16(v uint16, order binary.ByteOrder) {
+	var p [2]byte
+	putUint16(p[:], v, order)
+	d.add(p[:])
+}
+
+// WriteUint32 adds v, encoded in the byte order order, to the checksum.
+func (d *digest) WriteUint32(v uint32, order binary.ByteOrder) {
+	var p [4]byte
+	putUint32(p[:], v, order)
+	d.add(p[:])
+}
 
+func (d *digest) Sum16() uint16 { return d.params.finish(d.crc) }
+
+// Sum16Reset returns the checksum, as Sum16 does, and resets the digest
+// for the next message.
+func (d *digest) Sum16Reset() uint16 {
+	s := d.Sum16()
+	d.Reset()
+	return s
+}
+
+// String returns the name of the algorithm and the current checksum, such
+// as "crc16(MODBUS)=0x4B37". The name is that of the Params the digest was
+// created with, or "custom" if it is empty; New names digests using
+// ANSITable or CCITTTable "ANSI" or "CCITT" and other Tables "custom".
+func (d *digest) String() string {
+	name := d.params.Name
+	if name == "" {
+		name = "custom"
+	}
+	return fmt.Sprintf("crc16(%s)=0x%04X", name, d.Sum16())
+}
+
+// Sum appends the checksum to in in the byte order of the digest. Unless
+// set by NewWithOrder, it is big-endian, most significant byte first, as
+// used by XMODEM and other non-reflected algorithms.
+//
+// Like the built-in append, Sum stores the checksum in the spare capacity
+// of in if there is enough, overwriting whatever else shares that part of
+// the backing array. Pass in[:len(in):len(in)] to force a copy, or use
+// SumBytes.
 func (d *digest) Sum(in []byte) []byte {
+	b := d.SumBytes()
+	return append(in, b[:]...)
+}
+
+// SumBytes returns the checksum in the byte order of Sum.
+func (d *digest) SumBytes() [Size]byte {
+	var b [Size]byte
+	d.SumInto(b[:])
+	return b
+}
+
+// SumInto writes the checksum into b[:2] in the byte order of Sum and
+// returns 2. It returns io.ErrShortBuffer if b is shorter than 2 bytes.
+// Unlike Sum, it never allocates.
+func (d *digest) SumInto(b []byte) (int, error) {
+	if len(b) < Size {
+		return 0, io.ErrShortBuffer
+	}
+	putUint16(b, d.Sum16(), d.order)
+	return Size, nil
+}
+
+// putUint16 stores v into b[:2] in the byte order order, or big-endian if
+// order is nil. Unlike order.PutUint16, it does not make b escape.
+func putUint16(b []byte, v uint16, order binary.ByteOrder) {
+	switch order {
+	case nil, binary.BigEndian:
+		binary.BigEndian.PutUint16(b, v)
+	case binary.LittleEndian:
+		binary.LittleEndian.PutUint16(b, v)
+	default:
+		var t [2]byte
+		order.PutUint16(t[:], v)
+		copy(b, t[:])
+	}
+}
+
+// putUint32 is like putUint16 for a uint32 stored into b[:4].
+func putUint32(b []byte, v uint32, order binary.ByteOrder) {
+	switch order {
+	case nil, binary.BigEndian:
+		binary.BigEndian.PutUint32(b, v)
+	case binary.LittleEndian:
+		binary.LittleEndian.PutUint32(b, v)
+	default:
+		var t [4]byte
+		order.PutUint32(t[:], v)
+		copy(b, t[:])
+	}
+}
+
+// SumLE appends the checksum to in in little-endian order, least
+// significant byte first, as transmitted by Modbus RTU, KERMIT, X.25,
+// USB and other reflected algorithms.
+func (d *digest) SumLE(in []byte) []byte {
 	s := d.Sum16()
-	return append(in, byte(s>>8), byte(s))
+	return append(in, byte(s), byte(s>>8))
+}
+
+const (
+	magic         = "c16\x01"
+	marshaledSize = len(magic) + 2 + 2 + 8
+)
+
+func (d *digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	b = binary.BigEndian.AppendUint16(b, modelSum(d.tab, &d.params))
+	b = binary.BigEndian.AppendUint16(b, d.crc)
+	b = binary.BigEndian.AppendUint64(b, uint64(d.n))
+	return b, nil
+}
+
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("crc16: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("crc16: invalid hash state size")
+	}
+	if modelSum(d.tab, &d.params) != binary.BigEndian.Uint16(b[4:]) {
+		return errors.New("crc16: tables do not match")
+	}
+	d.crc = binary.BigEndian.Uint16(b[6:])
+	d.n = int64(binary.BigEndian.Uint64(b[8:]))
+	return nil
+}
+
+// modelSum returns the ANSI checksum of the table t and the parameters p,
+// identifying the algorithm of a marshaled digest.
+func modelSum(t *Table, p *Params) uint16 {
+	var a [512 + 5]byte
+	b := a[:0]
+	if t != nil {
+		for _, x := range t {
+			b = binary.BigEndian.AppendUint16(b, x)
+		}
+	}
+	b = binary.BigEndian.AppendUint16(b, p.Init)
+	b = binary.BigEndian.AppendUint16(b, p.XorOut)
+	var flags byte
+	if p.RefIn {
+		flags |= 1
+	}
+	if p.RefOut {
+		flags |= 2
+	}
+	b = append(b, flags)
+	return ChecksumANSI(b)
 }
 
 // Checksum returns the CRC-16 checksum of data
 // using the polynomial represented by the Table.
 func Checksum(data []byte, tab *Table) uint16 { return Update(0, tab, data) }
 
+// ChecksumAll stores the Checksum of each message in msgs using the Table
+// in out, which is grown if shorter than msgs, and returns out resliced to
+// len(msgs). It does not allocate if out is large enough.
+func ChecksumAll(msgs [][]byte, tab *Table, out []uint16) []uint16 {
+	if cap(out) < len(msgs) {
+		out = make([]uint16, len(msgs))
+	}
+	out = out[:len(msgs)]
+	for i, m := range msgs {
+		out[i] = Checksum(m, tab)
+	}
+	return out
+}
+
+// ErrNilTable is returned by ChecksumSafe when the Table is nil.
+var ErrNilTable = errors.New("crc16: nil table")
+
+// ChecksumSafe is like Checksum but returns ErrNilTable instead of
+// panicking when tab is nil.
+func ChecksumSafe(data []byte, tab *Table) (uint16, error) {
+	if tab == nil {
+		return 0, ErrNilTable
+	}
+	return Checksum(data, tab), nil
+}
+
+// ChecksumWith returns the CRC-16 checksum of data using the reflected
+// algorithm of Update with the Table, starting from the initial value init
+// instead of 0xFFFF and XORing the result with xorout. As in Params, init
+// is given before reflection.
+func ChecksumWith(data []byte, tab *Table, init, xorout uint16) uint16 {
+	return update(Reverse16(init), tab, data) ^ xorout
+}
+
+// SeedFromPrefix returns the shift register of the algorithm of Checksum
+// after the bytes of prefix, for use with UpdateSeeded. It lets a header
+// shared by many messages be hashed once.
+func SeedFromPrefix(prefix []byte, tab *Table) uint16 {
+	return update(0xffff, tab, prefix)
+}
+
+// UpdateSeeded returns the Checksum of the message made of the prefix
+// that produced seed, as returned by SeedFromPrefix, followed by p. Unlike
+// Update, it does not complement seed, which is already a shift register.
+func UpdateSeeded(seed uint16, tab *Table, p []byte) uint16 {
+	return ^update(seed, tab, p)
+}
+
 // ChecksumANSI returns the CRC-16 checksum of data
 // using the ANSI polynomial.
 func ChecksumANSI(data []byte) uint16 { return Update(0, ANSITable, data) }
 
 // ChecksumCCITT returns the CRC-16 checksum of data
-// using the CCITT polynomial.
+// using the CCITT polynomial. This is the CRC-16/X-25 checksum
+// used by HDLC.
 func ChecksumCCITT(data []byte) uint16 { return Update(0, CCITTTable, data) }
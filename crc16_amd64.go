@@ -0,0 +1,72 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains the AMD64-specific hardware-assisted CRC16 algorithm,
+// which folds 16 bytes at a time using carry-less multiplication.
+// crc16_otherarch.go provides the same interface for other architectures.
+
+package crc16
+
+import "encoding/binary"
+
+// hasPCLMULQDQ reports whether the CPU supports the PCLMULQDQ instruction.
+//
+//go:noescape
+func hasPCLMULQDQ() bool
+
+// foldCLMUL folds the 16-byte blocks of p, whose length is a multiple of
+// 16, into the 128-bit state x using the folding constants k.
+//
+//go:noescape
+func foldCLMUL(x *[2]uint64, k *[2]uint64, p []byte)
+
+var haveCLMUL = hasPCLMULQDQ()
+
+// archTable holds the folding constants of a reflected Table.
+type archTable [2]uint64
+
+// archMakeTable returns the constants folding the two 64-bit halves of the
+// state over 128 bits for the reflected Table tab: x^191 and x^127 modulo
+// the polynomial, reflected and aligned to the top of a 64-bit lane. The
+// exponents are one less than the folding distances of the halves since
+// the carry-less product of two reflected values is shifted by one bit.
+func archMakeTable(tab *Table) archTable {
+	return archTable{uint64(xnmod(191, tab)) << 48, uint64(xnmod(127, tab)) << 48}
+}
+
+// xnmod returns x^n modulo the polynomial of the reflected Table tab, in
+// reflected form.
+func xnmod(n int, tab *Table) uint16 {
+	poly := tab[0x80]
+	r := uint16(0x8000) // x^0
+	for i := 0; i < n; i++ {
+		r = (r >> 1) ^ (poly & -(r & 1))
+	}
+	return r
+}
+
+func archAvailable() bool { return haveCLMUL }
+
+// archUpdate returns the result of adding the bytes in p, at least 16, to
+// the shift register crc of the reflected algorithm with the derived
+// tables d.
+func archUpdate(crc uint16, d *derived, p []byte) uint16 {
+	k, st := (*[2]uint64)(&d.arch), d.slicing8
+
+	// The register is the same as zero XORed into the first two bytes.
+	n := len(p) &^ 15
+	x := [2]uint64{
+		binary.LittleEndian.Uint64(p) ^ uint64(crc),
+		binary.LittleEndian.Uint64(p[8:]),
+	}
+	foldCLMUL(&x, k, p[16:n])
+
+	// The state is congruent to the data folded into it, so its checksum
+	// from a zero register is that of the data.
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:], x[0])
+	binary.LittleEndian.PutUint64(b[8:], x[1])
+	crc = slicingUpdate(0, st, b[:])
+	return slicingUpdate(crc, st, p[n:])
+}
@@ -0,0 +1,45 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "textflag.h"
+
+// func hasPCLMULQDQ() bool
+TEXT ·hasPCLMULQDQ(SB), NOSPLIT, $0-1
+	MOVL $1, AX
+	XORL CX, CX
+	CPUID
+	SHRL $1, CX
+	ANDL $1, CX
+	MOVB CX, ret+0(FP)
+	RET
+
+// func foldCLMUL(x *[2]uint64, k *[2]uint64, p []byte)
+TEXT ·foldCLMUL(SB), NOSPLIT, $0-40
+	MOVQ x+0(FP), AX
+	MOVQ k+8(FP), BX
+	MOVQ p_base+16(FP), SI
+	MOVQ p_len+24(FP), CX
+	MOVOU (AX), X0
+	MOVOU (BX), X1
+
+loop:
+	CMPQ CX, $16
+	JB   done
+
+	// Multiply the low half by x^192 and the high half by x^128, modulo
+	// the polynomial, and add the next block.
+	MOVO      X0, X2
+	PCLMULQDQ $0x00, X1, X0
+	PCLMULQDQ $0x11, X1, X2
+	PXOR      X2, X0
+	MOVOU     (SI), X3
+	PXOR      X3, X0
+
+	ADDQ $16, SI
+	SUBQ $16, CX
+	JMP  loop
+
+done:
+	MOVOU X0, (AX)
+	RET
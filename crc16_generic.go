@@ -0,0 +1,140 @@
+// Copyright 2011 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains CRC16 algorithms that are not specific to any
+// architecture and don't use hardware acceleration.
+//
+// The simple (and slow) CRC16 implementation only uses a 256*2 bytes table.
+//
+// The slicing-by-8 algorithm is a faster implementation that uses a bigger
+// table (8*256*2 bytes).
+
+package crc16
+
+import "math/bits"
+
+// The simple algorithm is written once for registers of any width from 8
+// to 32 bits, so that it can be shared with CRCs of other widths. The
+// exported API instantiates it for uint16.
+
+// word is the set of shift register types supported by the simple
+// algorithm.
+type word interface {
+	~uint8 | ~uint16 | ~uint32
+}
+
+// width returns the number of bits in the register type T.
+func width[T word]() uint {
+	return uint(bits.Len64(uint64(^T(0))))
+}
+
+// simpleMakeTable returns the table of the reflected algorithm for the
+// polynomial poly, given in reversed notation.
+func simpleMakeTable[T word](poly T) *[256]T {
+	t := new([256]T)
+	for i := range t {
+		crc := T(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 == 1 {
+				crc = (crc >> 1) ^ poly
+			} else {
+				crc >>= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}
+
+// simpleMakeTableMSB returns the table of the non-reflected algorithm for
+// the polynomial poly, given in normal notation.
+func simpleMakeTableMSB[T word](poly T) *[256]T {
+	w := width[T]()
+	top := T(1) << (w - 1)
+	t := new([256]T)
+	for i := range t {
+		crc := T(i) << (w - 8)
+		for j := 0; j < 8; j++ {
+			if crc&top != 0 {
+				crc = (crc << 1) ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}
+
+// simpleUpdate uses the simple algorithm to update the shift register of
+// the reflected algorithm.
+func simpleUpdate[T word, Tab ~[256]T](crc T, tab *Tab, p []byte) T {
+	// The shift count is a variable because shifting a uint8 register
+	// out entirely is intended.
+	n := uint(8)
+	// Process fixed blocks of eight bytes to reduce the loop overhead.
+	// Slicing each block to a fixed length lets the compiler prove the
+	// indices in range.
+	for len(p) >= 8 {
+		q := p[:8:8]
+		crc = (*tab)[byte(crc)^q[0]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[1]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[2]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[3]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[4]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[5]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[6]] ^ (crc >> n)
+		crc = (*tab)[byte(crc)^q[7]] ^ (crc >> n)
+		p = p[8:]
+	}
+	for _, v := range p {
+		crc = (*tab)[byte(crc)^v] ^ (crc >> n)
+	}
+	return crc
+}
+
+// simpleUpdateMSB uses the simple algorithm to update the shift register
+// of the non-reflected algorithm.
+func simpleUpdateMSB[T word, Tab ~[256]T](crc T, tab *Tab, p []byte) T {
+	shift := width[T]() - 8
+	n := uint(8)
+	for _, v := range p {
+		crc = (*tab)[byte(crc>>shift)^v] ^ (crc << n)
+	}
+	return crc
+}
+
+// Use slicing-by-8 when payload >= this value.
+const slicing8Cutoff = 16
+
+// slicing8Table is array of 8 Tables, used by the slicing-by-8 algorithm.
+type slicing8Table [8]Table
+
+// slicingMakeTable constructs a slicing8Table from the reflected Table tab.
+// Element j of the result holds the contribution of a byte followed by j
+// zero bytes.
+func slicingMakeTable(tab *Table) *slicing8Table {
+	t := new(slicing8Table)
+	t[0] = *tab
+	for i := 0; i < 256; i++ {
+		crc := t[0][i]
+		for j := 1; j < 8; j++ {
+			crc = t[0][byte(crc)] ^ (crc >> 8)
+			t[j][i] = crc
+		}
+	}
+	return t
+}
+
+// slicingUpdate uses the slicing-by-8 algorithm to update the shift
+// register of the reflected algorithm.
+func slicingUpdate(crc uint16, tab *slicing8Table, p []byte) uint16 {
+	for len(p) > 8 {
+		crc ^= uint16(p[0]) | uint16(p[1])<<8
+		crc = tab[0][p[7]] ^ tab[1][p[6]] ^ tab[2][p[5]] ^ tab[3][p[4]] ^
+			tab[4][p[3]] ^ tab[5][p[2]] ^ tab[6][crc>>8] ^ tab[7][byte(crc)]
+		p = p[8:]
+	}
+	return simpleUpdate(crc, &tab[0], p)
+}
@@ -0,0 +1,65 @@
+package crc16
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestSimpleWidths(t *testing.T) {
+	// CRC-8/MAXIM-DOW and CRC-8/SMBUS.
+	if got := simpleUpdate(0, simpleMakeTable[uint8](0x8c), checkData); got != 0xa1 {
+		t.Errorf("CRC-8/MAXIM-DOW: got %#02x, want 0xa1", got)
+	}
+	if got := simpleUpdateMSB(0, simpleMakeTableMSB[uint8](0x07), checkData); got != 0xf4 {
+		t.Errorf("CRC-8/SMBUS: got %#02x, want 0xf4", got)
+	}
+
+	// CRC-32/ISO-HDLC, as implemented by hash/crc32, and CRC-32/BZIP2.
+	if got, want := ^simpleUpdate(^uint32(0), simpleMakeTable(uint32(crc32.IEEE)), checkData), crc32.ChecksumIEEE(checkData); got != want {
+		t.Errorf("CRC-32/ISO-HDLC: got %#08x, want %#08x", got, want)
+	}
+	if got := ^simpleUpdateMSB(^uint32(0), simpleMakeTableMSB[uint32](0x04c11db7), checkData); got != 0xfc891918 {
+		t.Errorf("CRC-32/BZIP2: got %#08x, want 0xfc891918", got)
+	}
+}
+
+// bitwiseUpdate is a reference implementation of the uint16 shift register
+// update that processes one bit at a time without a table.
+func bitwiseUpdate(crc, poly uint16, reflected bool, p []byte) uint16 {
+	for _, v := range p {
+		for i := 0; i < 8; i++ {
+			if reflected {
+				bit := (crc ^ uint16(v>>i)) & 1
+				crc >>= 1
+				if bit != 0 {
+					crc ^= poly
+				}
+			} else {
+				bit := (crc>>15 ^ uint16(v>>(7-i))) & 1
+				crc <<= 1
+				if bit != 0 {
+					crc ^= poly
+				}
+			}
+		}
+	}
+	return crc
+}
+
+func TestSimpleUint16(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	p := make([]byte, 1000)
+	rnd.Read(p)
+	for _, poly := range []uint16{ANSI, CCITT, 0xA001, 0x1021, 0x3d65} {
+		crc := uint16(rnd.Uint32())
+		for _, n := range []int{0, 1, 7, 8, 9, 15, 16, 17, len(p)} {
+			if got, want := simpleUpdate(crc, makeTable(poly), p[:n]), bitwiseUpdate(crc, poly, true, p[:n]); got != want {
+				t.Errorf("reflected %#04x, %d bytes: got %#04x, want %#04x", poly, n, got, want)
+			}
+		}
+		if got, want := simpleUpdateMSB(crc, makeTableMSB(poly), p), bitwiseUpdate(crc, poly, false, p); got != want {
+			t.Errorf("non-reflected %#04x: got %#04x, want %#04x", poly, got, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64
+
+package crc16
+
+type archTable struct{}
+
+func archMakeTable(tab *Table) archTable { return archTable{} }
+
+func archAvailable() bool { return false }
+
+func archUpdate(crc uint16, d *derived, p []byte) uint16 {
+	panic("crc16: no arch-specific implementation available")
+}
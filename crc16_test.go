@@ -1,6 +1,14 @@
 package crc16
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -59,3 +67,893 @@ func TestHelloWorld(t *testing.T) {
 		t.Fatal("Incorrect checksum for 'hello world'")
 	}
 }
+
+func TestCCITTCheck(t *testing.T) {
+	// CRC-16/X-25: reflected 0x1021, init 0xFFFF, final XOR 0xFFFF.
+	crc := ChecksumCCITT([]byte("123456789"))
+	if crc != 0x906e {
+		t.Fatalf("Incorrect CCITT check value: got %#04x, want 0x906e", crc)
+	}
+}
+
+func TestSlicingUpdate(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001)} {
+		st := slicingMakeTable(tab)
+		for n := 0; n < 100; n++ {
+			p := make([]byte, n)
+			rnd.Read(p)
+			crc := uint16(rnd.Uint32())
+			if got, want := slicingUpdate(crc, st, p), simpleUpdate(crc, tab, p); got != want {
+				t.Fatalf("length %d: slicing-by-8 got %#04x, want %#04x", n, got, want)
+			}
+		}
+	}
+}
+
+func TestDerivedTables(t *testing.T) {
+	// Every reflected Table built by the package takes the faster
+	// algorithms; Tables built by callers, which may be modified, do not.
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001), TableFor(Modbus)} {
+		if derivedFor(tab) == nil {
+			t.Errorf("%#04x: no derived tables", Reverse16(tab[0x80]))
+		}
+	}
+	copied, _ := TableFromSlice(MakeTable(0xa001).Slice())
+	for _, tab := range []*Table{ANSITableCopy(), copied, TableFor(XMODEM)} {
+		if derivedFor(tab) != nil {
+			t.Errorf("%#04x: unexpected derived tables", Reverse16(tab[0x80]))
+		}
+	}
+
+	p := make([]byte, 1000)
+	rand.New(rand.NewSource(1)).Read(p)
+	if got, want := ChecksumModbus(p), Modbus.checksum(copied, p); got != want {
+		t.Errorf("Modbus: got %#04x, want %#04x", got, want)
+	}
+}
+
+// sink keeps benchmarked results alive.
+var sink uint16
+
+func BenchmarkUpdate(b *testing.B) {
+	p := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(p)
+	st := slicingMakeTable(ANSITable)
+
+	b.Run("simple", func(b *testing.B) {
+		b.SetBytes(int64(len(p)))
+		for i := 0; i < b.N; i++ {
+			sink = simpleUpdate(0, ANSITable, p)
+		}
+	})
+	b.Run("slicing8", func(b *testing.B) {
+		b.SetBytes(int64(len(p)))
+		for i := 0; i < b.N; i++ {
+			sink = slicingUpdate(0, st, p)
+		}
+	})
+	b.Run("clmul", func(b *testing.B) {
+		if !archAvailable() {
+			b.Skip("no arch-specific implementation")
+		}
+		d := derivedFor(ANSITable)
+		b.SetBytes(int64(len(p)))
+		for i := 0; i < b.N; i++ {
+			sink = archUpdate(0, d, p)
+		}
+	})
+	b.Run("Modbus", func(b *testing.B) {
+		b.SetBytes(int64(len(p)))
+		for i := 0; i < b.N; i++ {
+			sink = ChecksumModbus(p)
+		}
+	})
+	b.Run("simple/64MiB", func(b *testing.B) {
+		big := make([]byte, 64<<20)
+		b.SetBytes(int64(len(big)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sink = simpleUpdate(0, ANSITable, big)
+		}
+	})
+	b.Run("simple/64", func(b *testing.B) {
+		b.SetBytes(64)
+		for i := 0; i < b.N; i++ {
+			sink = simpleUpdate(0, ANSITable, p[:64])
+		}
+	})
+}
+
+func TestArchUpdate(t *testing.T) {
+	if !archAvailable() {
+		t.Skip("no arch-specific implementation")
+	}
+	rnd := rand.New(rand.NewSource(1))
+	p := make([]byte, 4096)
+	rnd.Read(p)
+	tabs := []*Table{ANSITable, CCITTTable, MakeTable(0xa001), TableFor(DNP), TableFor(NRSC5)}
+	for i := 0; i < 4; i++ {
+		tabs = append(tabs, MakeTable(uint16(rnd.Uint32())|0x8000))
+	}
+	for _, tab := range tabs {
+		d := derivedFor(tab)
+		for _, n := range []int{16, 17, 31, 32, 33, 64, 100, 1000, len(p)} {
+			crc := uint16(rnd.Uint32())
+			if got, want := archUpdate(crc, d, p[:n]), simpleUpdate(crc, tab, p[:n]); got != want {
+				t.Errorf("%#04x, %d bytes: got %#04x, want %#04x", tab[0x80], n, got, want)
+			}
+		}
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	data := []byte("hello world")
+	for _, h := range []Hash16{NewANSI(), NewCCITT(), NewParams(XMODEM)} {
+		h.Write(data[:5])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := h.Sum16()
+		h.Write(data[5:])
+		final := h.Sum16()
+
+		h.Reset()
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		if got := h.Sum16(); got != want {
+			t.Fatalf("restored sum %#04x, want %#04x", got, want)
+		}
+		h.Write(data[5:])
+		if got := h.Sum16(); got != final {
+			t.Fatalf("resumed sum %#04x, want %#04x", got, final)
+		}
+	}
+}
+
+func TestUnmarshalBinaryMismatch(t *testing.T) {
+	h := NewANSI()
+	h.Write([]byte("hello"))
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range []Hash16{NewCCITT(), NewParams(Modbus)} {
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+			t.Error("state of a different table was accepted")
+		}
+	}
+	if err := NewANSI().(encoding.BinaryUnmarshaler).UnmarshalBinary(state[:5]); err == nil {
+		t.Error("truncated state was accepted")
+	}
+	if err := NewANSI().(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("crc\x01\x00\x00\x00\x00")); err == nil {
+		t.Error("state with an invalid identifier was accepted")
+	}
+}
+
+func TestSumByteOrder(t *testing.T) {
+	h := NewParams(Modbus)
+	h.Write(checkData)
+	if got := h.Sum([]byte{0xaa}); string(got) != "\xaa\x4b\x37" {
+		t.Errorf("Sum: got % x, want aa 4b 37", got)
+	}
+	if got := h.(*digest).SumLE([]byte{0xaa}); string(got) != "\xaa\x37\x4b" {
+		t.Errorf("SumLE: got % x, want aa 37 4b", got)
+	}
+}
+
+// The digest implements the optional writer interfaces.
+var (
+	_ io.StringWriter = (*digest)(nil)
+	_ io.ByteWriter   = (*digest)(nil)
+	_ io.ReaderFrom   = (*digest)(nil)
+)
+
+func TestWriteString(t *testing.T) {
+	h := NewANSI()
+	h.Write([]byte("abc"))
+	want := h.Sum16()
+
+	h.Reset()
+	h.(io.StringWriter).WriteString("abc")
+	if got := h.Sum16(); got != want {
+		t.Fatalf("WriteString: got %#04x, want %#04x", got, want)
+	}
+
+	// io.WriteString uses the WriteString method, which does not copy s.
+	h.Reset()
+	io.WriteString(h, "abc")
+	if got := h.Sum16(); got != want {
+		t.Fatalf("io.WriteString: got %#04x, want %#04x", got, want)
+	}
+	s := string(make([]byte, 1000))
+	if n := testing.AllocsPerRun(100, func() { io.WriteString(h, s) }); n != 0 {
+		t.Fatalf("io.WriteString: got %v allocations, want 0", n)
+	}
+
+	h.Reset()
+	for _, c := range []byte("abc") {
+		h.(io.ByteWriter).WriteByte(c)
+	}
+	if got := h.Sum16(); got != want {
+		t.Fatalf("WriteByte: got %#04x, want %#04x", got, want)
+	}
+}
+
+func BenchmarkWriteString(b *testing.B) {
+	s := "the quick brown fox jumps over the lazy dog"
+	h := NewANSI()
+
+	b.Run("Write", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			h.Write([]byte(s))
+		}
+	})
+	b.Run("WriteString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			io.WriteString(h, s)
+		}
+	})
+}
+
+func TestUpdateDirections(t *testing.T) {
+	msb := MakeTableParams(XMODEM)
+	tests := []struct {
+		name  string
+		got   uint16
+		check uint16
+	}{
+		{"X-25", Update(0, CCITTTable, checkData), 0x906e},
+		{"USB", Update(0, MakeTable(0xA001), checkData), 0xb4c8},
+		{"XMODEM", UpdateMSB(0x0000, msb, checkData), 0x31c3},
+		{"CCITT-FALSE", UpdateMSB(0xffff, msb, checkData), 0x29b1},
+		{"GENIBUS", UpdateMSB(0xffff, msb, checkData) ^ 0xffff, 0xd64e},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.check {
+			t.Errorf("%s: got %#04x, want %#04x", tt.name, tt.got, tt.check)
+		}
+	}
+
+	// Chaining continues from the previous register.
+	if got := UpdateMSB(UpdateMSB(0, msb, checkData[:4]), msb, checkData[4:]); got != 0x31c3 {
+		t.Errorf("chained XMODEM: got %#04x, want 0x31c3", got)
+	}
+}
+
+func TestChecksumWith(t *testing.T) {
+	tab := MakeTable(0xA001)
+	tests := []struct {
+		name         string
+		tab          *Table
+		init, xorout uint16
+		check        uint16
+	}{
+		{"ARC", tab, 0x0000, 0x0000, 0xbb3d},
+		{"MODBUS", tab, 0xffff, 0x0000, 0x4b37},
+		{"USB", tab, 0xffff, 0xffff, 0xb4c8},
+		{"RIELLO", CCITTTable, 0xb2aa, 0x0000, 0x63d0},
+	}
+	for _, tt := range tests {
+		if got := ChecksumWith(checkData, tt.tab, tt.init, tt.xorout); got != tt.check {
+			t.Errorf("%s: got %#04x, want %#04x", tt.name, got, tt.check)
+		}
+	}
+	if got, want := ChecksumWith(checkData, ANSITable, 0xffff, 0xffff), ChecksumANSI(checkData); got != want {
+		t.Errorf("got %#04x, want ChecksumANSI %#04x", got, want)
+	}
+}
+
+func TestMakeTableCache(t *testing.T) {
+	if MakeTable(0xA001) != MakeTable(0xA001) {
+		t.Fatal("MakeTable returned different tables for the same polynomial")
+	}
+
+	const poly = 0x1234
+	results := make(chan *Table)
+	for i := 0; i < 8; i++ {
+		go func() { results <- MakeTable(poly) }()
+	}
+	first := <-results
+	for i := 1; i < 8; i++ {
+		if <-results != first {
+			t.Fatal("concurrent MakeTable calls returned different tables")
+		}
+	}
+	if *first != *makeTable(poly) {
+		t.Fatal("cached table is incorrect")
+	}
+}
+
+func TestUpdateBits(t *testing.T) {
+	data := []byte("hello world")
+	for _, tab := range []*Table{ANSITable, CCITTTable} {
+		if got, want := UpdateBits(0, tab, data, 8*len(data)), Update(0, tab, data); got != want {
+			t.Errorf("whole bytes: got %#04x, want %#04x", got, want)
+		}
+
+		// Reference: feed the bits one at a time, least significant bit
+		// first for whole bytes and most significant bit first for the
+		// trailing partial byte.
+		poly := tab[0x80]
+		step := func(crc uint16, bit byte) uint16 {
+			if (crc^uint16(bit))&1 != 0 {
+				return (crc >> 1) ^ poly
+			}
+			return crc >> 1
+		}
+		crc := uint16(0xffff)
+		for i := 0; i < 8; i++ {
+			crc = step(crc, data[0]>>i&1)
+		}
+		for i := 7; i > 3; i-- {
+			crc = step(crc, data[1]>>i&1)
+		}
+		if got, want := UpdateBits(0, tab, data, 12), ^crc; got != want {
+			t.Errorf("12 bits: got %#04x, want %#04x", got, want)
+		}
+	}
+
+	for _, nbits := range []int{-1, -8, 8*len(data) + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d bits: no panic", nbits)
+				}
+			}()
+			UpdateBits(0, ANSITable, data, nbits)
+		}()
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		h    Hash16
+		want string
+	}{
+		{NewANSI(), "crc16(ANSI)=0x0000"},
+		{NewCCITT(), "crc16(CCITT)=0x0000"},
+		{New(MakeTable(0xA001)), "crc16(custom)=0x0000"},
+		{NewParams(Modbus), "crc16(MODBUS)=0xFFFF"},
+		{NewParams(Params{Poly: 0x1021}), "crc16(custom)=0x0000"},
+		{new(digest), "crc16(custom)=0x0000"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.h); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+
+	h := NewParams(Modbus)
+	h.Write(checkData)
+	if got, want := fmt.Sprint(h), "crc16(MODBUS)=0x4B37"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMakeTableRef(t *testing.T) {
+	ref := MakeTableRef(0x8408, true)
+	msb := MakeTableRef(0x1021, false)
+	if ref != CCITTTable {
+		t.Error("reflected CCITT table is not CCITTTable")
+	}
+	if msb != MakeTableRef(0x1021, false) {
+		t.Error("non-reflected tables are not cached")
+	}
+	if *msb == *MakeTable(0x1021) {
+		t.Error("non-reflected table equals the reflected table")
+	}
+
+	if got := ChecksumWith(checkData, ref, 0, 0); got != 0x2189 {
+		t.Errorf("KERMIT: got %#04x, want 0x2189", got)
+	}
+	if got := Update(0, ref, checkData); got != 0x906e {
+		t.Errorf("X-25: got %#04x, want 0x906e", got)
+	}
+	if got := UpdateMSB(0, msb, checkData); got != 0x31c3 {
+		t.Errorf("XMODEM: got %#04x, want 0x31c3", got)
+	}
+	if got := UpdateMSB(0xffff, msb, checkData); got != 0x29b1 {
+		t.Errorf("CCITT-FALSE: got %#04x, want 0x29b1", got)
+	}
+}
+
+func TestUpdateBitwise(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, poly := range []uint16{ANSI, CCITT} {
+		tab := MakeTable(poly)
+		for i := 0; i < 100; i++ {
+			p := make([]byte, rnd.Intn(100))
+			rnd.Read(p)
+			crc := uint16(rnd.Uint32())
+			if got, want := UpdateBitwise(crc, poly, p), Update(crc, tab, p); got != want {
+				t.Fatalf("poly %#04x: got %#04x, want %#04x", poly, got, want)
+			}
+		}
+	}
+}
+
+func TestSumInto(t *testing.T) {
+	h := NewParams(Modbus)
+	h.Write(checkData)
+	d := h.(*digest)
+
+	b := []byte{0, 0, 0xaa}
+	if n, err := d.SumInto(b); n != 2 || err != nil {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if string(b) != "\x4b\x37\xaa" {
+		t.Fatalf("got % x, want 4b 37 aa", b)
+	}
+	if _, err := d.SumInto(b[:1]); err != io.ErrShortBuffer {
+		t.Fatalf("got error %v, want io.ErrShortBuffer", err)
+	}
+}
+
+func BenchmarkSumInto(b *testing.B) {
+	d := NewParams(Modbus).(*digest)
+	frame := make([]byte, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Reset()
+		d.Write(frame[:62])
+		d.SumInto(frame[62:])
+	}
+}
+
+func TestChecksumSafe(t *testing.T) {
+	if crc, err := ChecksumSafe(checkData, nil); crc != 0 || err != ErrNilTable {
+		t.Errorf("nil table: got %#04x, %v", crc, err)
+	}
+	crc, err := ChecksumSafe(checkData, ANSITable)
+	if want := ChecksumANSI(checkData); crc != want || err != nil {
+		t.Errorf("got %#04x, %v, want %#04x", crc, err, want)
+	}
+}
+
+func TestClone(t *testing.T) {
+	for _, h := range []Hash16{NewANSI(), NewParams(XMODEM)} {
+		h.Write([]byte("1234"))
+		c := h.(*digest).Clone()
+		h.Write([]byte("56789"))
+		c.Write([]byte("5678X"))
+
+		r := NewParams(h.(*digest).Params())
+		r.Write(checkData)
+		if got, want := h.Sum16(), r.Sum16(); got != want {
+			t.Errorf("original: got %#04x, want %#04x", got, want)
+		}
+		r.Reset()
+		r.Write([]byte("12345678X"))
+		if got, want := c.Sum16(), r.Sum16(); got != want {
+			t.Errorf("clone: got %#04x, want %#04x", got, want)
+		}
+	}
+}
+
+func TestNewWithOrder(t *testing.T) {
+	tests := []struct {
+		order binary.ByteOrder
+		want  string
+	}{
+		{binary.BigEndian, "\x90\x6e"},
+		{binary.LittleEndian, "\x6e\x90"},
+		{nil, "\x90\x6e"},
+	}
+	for _, tt := range tests {
+		h := NewWithOrder(CCITTTable, tt.order)
+		h.Write(checkData)
+		if got := h.Sum(nil); string(got) != tt.want {
+			t.Errorf("%v: Sum got % x, want % x", tt.order, got, tt.want)
+		}
+		var b [2]byte
+		h.(*digest).SumInto(b[:])
+		if string(b[:]) != tt.want {
+			t.Errorf("%v: SumInto got % x, want % x", tt.order, b, tt.want)
+		}
+	}
+
+	h := NewCCITT()
+	h.Write(checkData)
+	if got := h.Sum(nil); string(got) != "\x90\x6e" {
+		t.Errorf("New: Sum got % x, want 90 6e", got)
+	}
+}
+
+func FuzzUpdateChaining(f *testing.F) {
+	f.Add([]byte{}, []byte{})
+	f.Add([]byte{0}, []byte{})
+	f.Add([]byte{}, []byte{0xff})
+	f.Add([]byte{0x31}, []byte{0x32})
+	f.Add(checkData, make([]byte, 40))
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		ab := append(append([]byte{}, a...), b...)
+		for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001)} {
+			want := Checksum(ab, tab)
+			if got := Update(Update(0, tab, a), tab, b); got != want {
+				t.Errorf("Update chain: got %#04x, want %#04x", got, want)
+			}
+			if got := Update(Update(Update(0, tab, a), tab, nil), tab, b); got != want {
+				t.Errorf("Update chain with empty write: got %#04x, want %#04x", got, want)
+			}
+			h := New(tab)
+			h.Write(a)
+			h.Write(nil)
+			h.Write(b)
+			if got := h.Sum16(); got != want {
+				t.Errorf("digest: got %#04x, want %#04x", got, want)
+			}
+		}
+	})
+}
+
+func TestChecksumAll(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	msgs := make([][]byte, 100)
+	for i := range msgs {
+		msgs[i] = make([]byte, rnd.Intn(40))
+		rnd.Read(msgs[i])
+	}
+
+	for _, out := range [][]uint16{nil, make([]uint16, 3), make([]uint16, 0, 200)} {
+		got := ChecksumAll(msgs, CCITTTable, out)
+		if len(got) != len(msgs) {
+			t.Fatalf("got %d checksums, want %d", len(got), len(msgs))
+		}
+		for i, m := range msgs {
+			if want := Checksum(m, CCITTTable); got[i] != want {
+				t.Fatalf("message %d: got %#04x, want %#04x", i, got[i], want)
+			}
+		}
+	}
+}
+
+func BenchmarkChecksumAll(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	msgs := make([][]byte, 10000)
+	n := 0
+	for i := range msgs {
+		msgs[i] = make([]byte, 8+rnd.Intn(56))
+		rnd.Read(msgs[i])
+		n += len(msgs[i])
+	}
+	out := make([]uint16, len(msgs))
+	b.SetBytes(int64(n))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = ChecksumAll(msgs, ANSITable, out)
+	}
+}
+
+func TestSumAliasing(t *testing.T) {
+	h := NewCCITT()
+	h.Write(checkData)
+
+	// Sum appends in place when in has spare capacity, overwriting the
+	// bytes that follow it in the backing array.
+	buf := []byte("abcdef")
+	if got := h.Sum(buf[:2]); string(got) != "ab\x90\x6e" {
+		t.Fatalf("got % x", got)
+	}
+	if string(buf) != "ab\x90\x6eef" {
+		t.Fatalf("Sum did not append in place: backing array is % x", buf)
+	}
+
+	// A full slice expression forces Sum to copy.
+	buf = []byte("abcdef")
+	if got := h.Sum(buf[:2:2]); string(got) != "ab\x90\x6e" {
+		t.Fatalf("got % x", got)
+	}
+	if string(buf) != "abcdef" {
+		t.Fatalf("Sum modified the backing array: % x", buf)
+	}
+
+	if got := h.(*digest).SumBytes(); got != [2]byte{0x90, 0x6e} {
+		t.Fatalf("SumBytes: got % x, want 90 6e", got)
+	}
+}
+
+func TestWriteUint(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		b := make([]byte, 6)
+		order.PutUint16(b, 0x1234)
+		order.PutUint32(b[2:], 0xdeadbeef)
+		want := ChecksumANSI(b)
+
+		d := NewANSI().(*digest)
+		d.WriteUint16(0x1234, order)
+		d.WriteUint32(0xdeadbeef, order)
+		if got := d.Sum16(); got != want {
+			t.Errorf("%v: got %#04x, want %#04x", order, got, want)
+		}
+	}
+
+	d := NewANSI().(*digest)
+	if n := testing.AllocsPerRun(100, func() {
+		d.WriteUint16(0x1234, binary.LittleEndian)
+		d.WriteUint32(0xdeadbeef, binary.BigEndian)
+	}); n != 0 {
+		t.Errorf("got %v allocations, want 0", n)
+	}
+}
+
+func TestWriteAfterSum(t *testing.T) {
+	a, b := []byte("hello "), []byte("world")
+	ab := append(append([]byte{}, a...), b...)
+	for _, f := range []Factory{NewANSI, NewCCITT, NewXMODEM, NewProfibus, FactoryParams(MAXIM)} {
+		h := f()
+		h.Write(a)
+		h.Sum(nil)
+		h.(*digest).SumBytes()
+		h.(*digest).SumLE(nil)
+		_ = h.(*digest).String()
+		h.Sum16()
+		h.Write(b)
+		h.Sum(nil)
+
+		want := f()
+		want.Write(ab)
+		if got := h.Sum16(); got != want.Sum16() {
+			t.Errorf("%s: got %#04x, want %#04x", h.(*digest).Params().Name, got, want.Sum16())
+		}
+	}
+}
+
+func TestSum16Reset(t *testing.T) {
+	d := NewParams(Modbus).(*digest)
+	for _, rec := range []string{"123456789", "hello world", "123456789"} {
+		d.WriteString(rec)
+		if got, want := d.Sum16Reset(), ChecksumModbus([]byte(rec)); got != want {
+			t.Errorf("%q: got %#04x, want %#04x", rec, got, want)
+		}
+	}
+}
+
+func TestMakeTableChecked(t *testing.T) {
+	if tab, err := MakeTableChecked(0); tab != nil || err != ErrZeroPoly {
+		t.Errorf("poly 0: got %p, %v", tab, err)
+	}
+	if tab, err := MakeTableChecked(CCITT); tab != CCITTTable || err != nil {
+		t.Errorf("CCITT: got %p, %v", tab, err)
+	}
+}
+
+func TestWriteC(t *testing.T) {
+	var buf bytes.Buffer
+	if err := MakeTable(0xa001).WriteC(&buf, "crc16_table"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	// The start and end of the widely published CRC-16/ARC table.
+	head := "static const uint16_t crc16_table[256] = {\n" +
+		"    0x0000, 0xC0C1, 0xC181, 0x0140, 0xC301, 0x03C0, 0x0280, 0xC241,\n" +
+		"    0xC601, 0x06C0, 0x0780, 0xC741, 0x0500, 0xC5C1, 0xC481, 0x0440,\n"
+	tail := "    0x4400, 0x84C1, 0x8581, 0x4540, 0x8701, 0x47C0, 0x4680, 0x8641,\n" +
+		"    0x8201, 0x42C0, 0x4380, 0x8341, 0x4100, 0x81C1, 0x8081, 0x4040,\n" +
+		"};\n"
+	if !strings.HasPrefix(out, head) {
+		t.Errorf("output starts with:\n%s", out[:len(head)])
+	}
+	if !strings.HasSuffix(out, tail) {
+		t.Errorf("output ends with:\n%s", out[len(out)-len(tail):])
+	}
+	if n := strings.Count(out, "\n"); n != 34 {
+		t.Errorf("got %d lines, want 34", n)
+	}
+}
+
+func TestTableSlice(t *testing.T) {
+	tab := MakeTable(0xa6bc)
+	s := tab.Slice()
+	if len(s) != 256 {
+		t.Fatalf("got %d entries, want 256", len(s))
+	}
+	s[1] ^= 1
+	if tab[1] == s[1] {
+		t.Error("Slice shares its entries with the Table")
+	}
+	s[1] ^= 1
+
+	got, err := TableFromSlice(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *tab {
+		t.Error("round trip changed the Table")
+	}
+	if Checksum(checkData, got) != Checksum(checkData, tab) {
+		t.Error("restored Table computes a different checksum")
+	}
+
+	for _, n := range []int{0, 255, 257} {
+		if _, err := TableFromSlice(make([]uint16, n)); err != ErrTableLength {
+			t.Errorf("%d entries: got %v, want ErrTableLength", n, err)
+		}
+	}
+}
+
+func TestResetWith(t *testing.T) {
+	d := NewANSI().(*digest)
+	d.Write([]byte("garbage"))
+	d.ResetWith(CCITTTable)
+	d.Write(checkData)
+	f := NewCCITT()
+	f.Write(checkData)
+	if d.Sum16() != f.Sum16() || d.Params() != f.(*digest).Params() {
+		t.Errorf("ResetWith: got %v, want %v", d, f)
+	}
+
+	d.ResetParams(XMODEM)
+	d.Write(checkData)
+	if got := d.Sum16(); got != 0x31c3 {
+		t.Errorf("ResetParams: got %#04x, want 0x31c3", got)
+	}
+}
+
+func TestTableCopy(t *testing.T) {
+	want := ChecksumANSI(checkData)
+	for _, c := range []*Table{ANSITableCopy(), CCITTTableCopy()} {
+		if c == ANSITable || c == CCITTTable {
+			t.Fatal("copy is the shared table")
+		}
+		for i := range c {
+			c[i] = 0
+		}
+	}
+	if got := ChecksumANSI(checkData); got != want {
+		t.Errorf("ChecksumANSI changed from %#04x to %#04x", want, got)
+	}
+	if *ANSITableCopy() != *ANSITable || *CCITTTableCopy() != *CCITTTable {
+		t.Error("copies differ from the shared tables")
+	}
+}
+
+func TestSumBytes(t *testing.T) {
+	for _, tt := range []struct {
+		order binary.ByteOrder
+		want  [2]byte
+	}{
+		{binary.BigEndian, [2]byte{0x90, 0x6e}},
+		{binary.LittleEndian, [2]byte{0x6e, 0x90}},
+	} {
+		d := NewWithOrder(CCITTTable, tt.order).(*digest)
+		d.Write(checkData)
+		if got := d.SumBytes(); got != tt.want {
+			t.Errorf("%v: got % x, want % x", tt.order, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkSumBytes(b *testing.B) {
+	d := NewWithOrder(CCITTTable, binary.LittleEndian).(*digest)
+	frame := make([]byte, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Reset()
+		d.Write(frame[:62])
+		s := d.SumBytes()
+		copy(frame[62:], s[:])
+	}
+}
+
+func TestUpdateSeeded(t *testing.T) {
+	header := []byte("\x7e\xff\x03")
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001)} {
+		seed := SeedFromPrefix(header, tab)
+		for _, suffix := range []string{"", "1", "123456789", "hello world"} {
+			msg := append(append([]byte{}, header...), suffix...)
+			if got, want := UpdateSeeded(seed, tab, []byte(suffix)), Checksum(msg, tab); got != want {
+				t.Errorf("%q: got %#04x, want %#04x", suffix, got, want)
+			}
+		}
+		if got, want := SeedFromPrefix(nil, tab), uint16(0xffff); got != want {
+			t.Errorf("empty prefix: got %#04x, want %#04x", got, want)
+		}
+	}
+}
+
+func TestConcurrentChecksum(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	bufs := make([][]byte, 64)
+	want := make([]uint16, len(bufs))
+	for i := range bufs {
+		bufs[i] = make([]byte, rnd.Intn(4096))
+		rnd.Read(bufs[i])
+		want[i] = Checksum(bufs[i], ANSITable)
+	}
+
+	// Besides the shared ANSITable, the goroutines race to build and cache
+	// the same new tables.
+	var wg sync.WaitGroup
+	for i := range bufs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if got := Checksum(bufs[i], ANSITable); got != want[i] {
+					t.Errorf("buffer %d: got %#04x, want %#04x", i, got, want[i])
+					return
+				}
+				tab := MakeTable(uint16(0x1000 + j))
+				h := New(tab)
+				h.Write(bufs[i])
+				if got := h.Sum16(); got != Update(0, tab, bufs[i]) {
+					t.Errorf("buffer %d: digest and Update disagree", i)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestNewInit(t *testing.T) {
+	h := NewInit(MakeTable(0xa001), 0)
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0xbb3d {
+		t.Errorf("ARC: got %#04x, want 0xbb3d", got)
+	}
+
+	for _, init := range []uint16{0, 0x1234, 0xffff} {
+		h := NewInit(ANSITable, init)
+		h.Write(checkData)
+		if got, want := h.Sum16(), ChecksumWith(checkData, ANSITable, init, 0); got != want {
+			t.Errorf("init %#04x: got %#04x, want %#04x", init, got, want)
+		}
+		if p := h.(*digest).Params(); p.Init != init || p.XorOut != 0 {
+			t.Errorf("init %#04x: got params %+v", init, p)
+		}
+	}
+}
+
+func TestReflected(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		h    Hash16
+		want bool
+	}{
+		{"ANSI", NewANSI(), true},
+		{"CCITT", NewCCITT(), true},
+		{"ARC", NewParams(ARC), true},
+		{"XMODEM", NewParams(XMODEM), false},
+	} {
+		if got := c.h.(*digest).Reflected(); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestWritten(t *testing.T) {
+	d := NewParams(Modbus).(*digest)
+	d.Write([]byte("1234"))
+	d.WriteString("567")
+	d.WriteByte('8')
+	d.WriteUint32(0, binary.BigEndian)
+	if got := d.Written(); got != 12 {
+		t.Errorf("got %d bytes, want 12", got)
+	}
+
+	state, _ := d.MarshalBinary()
+	r := NewParams(Modbus).(*digest)
+	if err := r.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	if r.Written() != 12 || r.Sum16() != d.Sum16() {
+		t.Errorf("restored %d bytes, %#04x; want 12 bytes, %#04x", r.Written(), r.Sum16(), d.Sum16())
+	}
+
+	if err := r.UnmarshalBinary(state[:8]); err == nil {
+		t.Error("state without the byte count accepted")
+	}
+
+	d.Reset()
+	if got := d.Written(); got != 0 {
+		t.Errorf("got %d bytes after Reset, want 0", got)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrMismatch is returned when a frame does not hold the checksum of its
+// contents.
+var ErrMismatch = errors.New("crc16: checksum mismatch")
+
+// A CRCError reports a frame that does not hold the checksum of its
+// contents. It matches ErrMismatch with errors.Is.
+type CRCError struct {
+	Want   uint16 // checksum stored in the frame
+	Got    uint16 // checksum computed from the frame contents
+	Offset int64  // offset of the frame in the stream, or zero
+}
+
+func (e *CRCError) Error() string {
+	return fmt.Sprintf("crc16: checksum mismatch at offset %d: frame holds 0x%04X, computed 0x%04X", e.Offset, e.Want, e.Got)
+}
+
+// Is reports whether target is ErrMismatch.
+func (e *CRCError) Is(target error) bool { return target == ErrMismatch }
+
+// A FrameReader reads frames made of a big-endian 2-byte payload length,
+// the payload and the big-endian Checksum of the length and payload.
+type FrameReader struct {
+	r   io.Reader
+	tab *Table
+	buf []byte
+	off int64 // bytes read from r
+}
+
+// NewFrameReader returns a FrameReader reading frames from r, checksummed
+// using the Table.
+func NewFrameReader(r io.Reader, tab *Table) *FrameReader {
+	return &FrameReader{r: r, tab: tab}
+}
+
+// Next reads the next frame and returns its payload, which is only valid
+// until the next call to Next. It returns io.EOF if there are no more
+// frames, io.ErrUnexpectedEOF if the stream ends inside a frame, and a
+// *CRCError if the checksum of the frame is wrong, in which case the
+// reader is positioned at the following frame.
+func (f *FrameReader) Next() ([]byte, error) {
+	start := f.off
+	var hdr [2]byte
+	m, err := io.ReadFull(f.r, hdr[:])
+	f.off += int64(m)
+	if err != nil {
+		return nil, err
+	}
+	n := int(hdr[0])<<8 | int(hdr[1])
+	if cap(f.buf) < 2+n+Size {
+		f.buf = make([]byte, 2+n+Size)
+	}
+	frame := f.buf[:2+n+Size]
+	copy(frame, hdr[:])
+	m, err = io.ReadFull(f.r, frame[2:])
+	f.off += int64(m)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if ok, want, got := Check(frame, f.tab); !ok {
+		return nil, &CRCError{Want: want, Got: got, Offset: start}
+	}
+	return frame[2 : 2+n], nil
+}
@@ -0,0 +1,92 @@
+package crc16
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// appendFrame appends the frame holding payload to b.
+func appendFrame(b, payload []byte, tab *Table) []byte {
+	frame := []byte{byte(len(payload) >> 8), byte(len(payload))}
+	frame = Append(append(frame, payload...), tab)
+	return append(b, frame...)
+}
+
+func TestFrameReader(t *testing.T) {
+	payloads := [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte("x"), 300)}
+	var stream []byte
+	for _, p := range payloads {
+		stream = appendFrame(stream, p, CCITTTable)
+	}
+
+	readers := []io.Reader{
+		bytes.NewReader(stream),
+		iotest.OneByteReader(bytes.NewReader(stream)),
+	}
+	for _, r := range readers {
+		f := NewFrameReader(r, CCITTTable)
+		for i, want := range payloads {
+			got, err := f.Next()
+			if err != nil {
+				t.Fatalf("frame %d: %v", i, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("frame %d: got %q, want %q", i, got, want)
+			}
+		}
+		if _, err := f.Next(); err != io.EOF {
+			t.Fatalf("got error %v at end of stream, want io.EOF", err)
+		}
+	}
+}
+
+func TestFrameReaderCorrupted(t *testing.T) {
+	stream := appendFrame(nil, []byte("hello"), CCITTTable)
+	stream = appendFrame(stream, []byte("world"), CCITTTable)
+	stream[3] ^= 0x10
+
+	f := NewFrameReader(bytes.NewReader(stream), CCITTTable)
+	if _, err := f.Next(); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("got error %v, want ErrMismatch", err)
+	}
+	// The next frame is still readable.
+	if got, err := f.Next(); err != nil || string(got) != "world" {
+		t.Fatalf("got %q, %v after mismatch", got, err)
+	}
+}
+
+func TestFrameReaderCRCError(t *testing.T) {
+	stream := appendFrame(nil, []byte("hello"), CCITTTable)
+	second := len(stream)
+	stream = appendFrame(stream, []byte("world"), CCITTTable)
+	stream[len(stream)-1] ^= 0xff
+
+	f := NewFrameReader(bytes.NewReader(stream), CCITTTable)
+	if _, err := f.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := f.Next()
+	var crcErr *CRCError
+	if !errors.As(err, &crcErr) {
+		t.Fatalf("got error %v, want a *CRCError", err)
+	}
+	frame := stream[second:]
+	want := uint16(frame[len(frame)-2])<<8 | uint16(frame[len(frame)-1])
+	got := Checksum(frame[:len(frame)-2], CCITTTable)
+	if crcErr.Want != want || crcErr.Got != got || crcErr.Offset != int64(second) {
+		t.Errorf("got %+v, want Want %#04x, Got %#04x, Offset %d", *crcErr, want, got, second)
+	}
+}
+
+func TestFrameReaderTruncated(t *testing.T) {
+	stream := appendFrame(nil, []byte("hello"), CCITTTable)
+	for n := 1; n < len(stream); n++ {
+		f := NewFrameReader(bytes.NewReader(stream[:n]), CCITTTable)
+		if _, err := f.Next(); err != io.ErrUnexpectedEOF {
+			t.Errorf("%d bytes: got error %v, want io.ErrUnexpectedEOF", n, err)
+		}
+	}
+}
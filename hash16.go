@@ -11,3 +11,20 @@ type Hash16 interface {
 	hash.Hash
 	Sum16() uint16
 }
+
+// As32 returns a hash.Hash32 computing the same checksum as h, for APIs
+// that require 32-bit hashes. The checksum is zero-extended: Sum32 returns
+// uint32(h.Sum16()) and Sum appends 4 bytes, two zero bytes followed by
+// the checksum, most significant byte first.
+func As32(h Hash16) hash.Hash32 { return hash32{h} }
+
+type hash32 struct{ Hash16 }
+
+func (h hash32) Size() int { return 4 }
+
+func (h hash32) Sum32() uint32 { return uint32(h.Sum16()) }
+
+func (h hash32) Sum(in []byte) []byte {
+	s := h.Sum16()
+	return append(in, 0, 0, byte(s>>8), byte(s))
+}
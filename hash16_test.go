@@ -0,0 +1,23 @@
+package crc16
+
+import (
+	"testing"
+)
+
+func TestAs32(t *testing.T) {
+	h := As32(NewCCITT())
+	h.Write(checkData)
+	if got := h.Sum32(); got != 0x906e {
+		t.Errorf("Sum32: got %#08x, want 0x0000906e", got)
+	}
+	if got := h.Sum([]byte{0xaa}); string(got) != "\xaa\x00\x00\x90\x6e" {
+		t.Errorf("Sum: got % x, want aa 00 00 90 6e", got)
+	}
+	if h.Size() != 4 {
+		t.Errorf("Size: got %d, want 4", h.Size())
+	}
+	h.Reset()
+	if got := h.Sum32(); got != 0 {
+		t.Errorf("Sum32 after Reset: got %#08x, want 0", got)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import "encoding/binary"
+
+// A Model is a CRC-16 algorithm together with the byte order of the
+// checksum in a frame. The zero Order is big-endian. Models are plain
+// values; the Table for the algorithm is cached as by TableFor.
+type Model struct {
+	Params Params
+	Order  binary.ByteOrder
+}
+
+// Checksum returns the checksum of data using the algorithm of m.
+func (m Model) Checksum(data []byte) uint16 {
+	return m.Params.checksum(TableFor(m.Params), data)
+}
+
+// New creates a new Hash16 computing the checksum of m. Its Sum and
+// SumInto methods store the checksum in the byte order of m.
+func (m Model) New() Hash16 {
+	d := &digest{tab: TableFor(m.Params), params: m.Params, order: m.Order}
+	d.Reset()
+	return d
+}
+
+// Append appends the checksum of frame to frame in the byte order of m
+// and returns the extended slice.
+func (m Model) Append(frame []byte) []byte {
+	var b [Size]byte
+	putUint16(b[:], m.Checksum(frame), m.Order)
+	return append(frame, b[:]...)
+}
+
+// Verify reports whether the last two bytes of frame hold the checksum of
+// the preceding bytes, stored in the byte order of m.
+func (m Model) Verify(frame []byte) bool {
+	n := len(frame) - Size
+	if n < 0 {
+		return false
+	}
+	var b [Size]byte
+	putUint16(b[:], m.Checksum(frame[:n]), m.Order)
+	return frame[n] == b[0] && frame[n+1] == b[1]
+}
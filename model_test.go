@@ -0,0 +1,42 @@
+package crc16
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestModel(t *testing.T) {
+	rtu := Model{Params: Modbus, Order: binary.LittleEndian}
+	if got := rtu.Checksum(checkData); got != 0x4b37 {
+		t.Errorf("Checksum: got %#04x, want 0x4b37", got)
+	}
+	// The example request of the Modbus specification.
+	pdu := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a}
+	frame := rtu.Append(pdu[:len(pdu):len(pdu)])
+	if string(frame) != string(AppendModbus(pdu[:len(pdu):len(pdu)])) {
+		t.Errorf("Append: got % x", frame)
+	}
+	if !rtu.Verify(frame) {
+		t.Error("Verify rejected the frame")
+	}
+	frame[1] ^= 1
+	if rtu.Verify(frame) {
+		t.Error("Verify accepted a corrupted frame")
+	}
+	if rtu.Verify([]byte{0}) {
+		t.Error("Verify accepted a short frame")
+	}
+
+	h := rtu.New()
+	h.Write(checkData)
+	if got := h.Sum(nil); string(got) != "\x37\x4b" {
+		t.Errorf("New: Sum got % x, want 37 4b", got)
+	}
+
+	// The zero Order is big-endian.
+	xm := Model{Params: XMODEM}
+	frame = xm.Append([]byte("123456789"))
+	if string(frame) != "123456789\x31\xc3" || !xm.Verify(frame) {
+		t.Errorf("XMODEM: got % x", frame)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import "encoding/binary"
+
+// An Option configures the digest created by NewHash.
+type Option func(*hashConfig)
+
+type hashConfig struct {
+	p     Params
+	tab   *Table // nil to use TableFor(p)
+	order binary.ByteOrder
+}
+
+// WithPoly sets the polynomial, in normal notation as in Params.Poly.
+func WithPoly(poly uint16) Option {
+	return func(c *hashConfig) { c.p.Poly, c.tab = poly, nil }
+}
+
+// WithInit sets the initial value, before reflection as in Params.Init.
+func WithInit(init uint16) Option {
+	return func(c *hashConfig) { c.p.Init = init }
+}
+
+// WithReflected sets whether input bytes and the result are reflected, as
+// Params.RefIn and Params.RefOut.
+func WithReflected(reflected bool) Option {
+	return func(c *hashConfig) { c.p.RefIn, c.p.RefOut, c.tab = reflected, reflected, nil }
+}
+
+// WithXorOut sets the value XORed into the result, as in Params.XorOut.
+func WithXorOut(xorout uint16) Option {
+	return func(c *hashConfig) { c.p.XorOut = xorout }
+}
+
+// WithByteOrder sets the byte order of Sum, as in NewWithOrder.
+func WithByteOrder(order binary.ByteOrder) Option {
+	return func(c *hashConfig) { c.order = order }
+}
+
+// WithTable selects the algorithm of New(tab): the reflected algorithm of
+// Update with the Table, with an initial value and final XOR of 0xFFFF.
+// Later options may change them.
+func WithTable(tab *Table) Option {
+	return func(c *hashConfig) { c.p, c.tab = tableParams(tab), tab }
+}
+
+// NewHash creates a new Hash16 configured by the options, applied in
+// order. Parameters not set by an option are zero: the polynomial, the
+// initial value and the final XOR are 0, bytes are not reflected, and Sum
+// is big-endian. For example, CRC-16/MODBUS is
+//
+//	NewHash(WithPoly(0x8005), WithInit(0xFFFF), WithReflected(true))
+func NewHash(opts ...Option) Hash16 {
+	var c hashConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.tab == nil {
+		c.tab = TableFor(c.p)
+	}
+	d := &digest{tab: c.tab, params: c.p, order: c.order}
+	d.Reset()
+	return d
+}
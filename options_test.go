@@ -0,0 +1,33 @@
+package crc16
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestNewHash(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Hash16
+		want Hash16
+	}{
+		{"MODBUS", NewHash(WithPoly(0x8005), WithInit(0xffff), WithReflected(true)), NewModbus()},
+		{"XMODEM", NewHash(WithPoly(0x1021)), NewXMODEM()},
+		{"GENIBUS", NewHash(WithPoly(0x1021), WithInit(0xffff), WithXorOut(0xffff)), NewParams(GENIBUS)},
+		{"ANSI", NewHash(WithTable(ANSITable)), NewANSI()},
+		{"ARC", NewHash(WithTable(MakeTable(0xa001)), WithInit(0), WithXorOut(0)), NewARC()},
+	}
+	for _, tt := range tests {
+		tt.h.Write(checkData)
+		tt.want.Write(checkData)
+		if got, want := tt.h.Sum16(), tt.want.Sum16(); got != want {
+			t.Errorf("%s: got %#04x, want %#04x", tt.name, got, want)
+		}
+	}
+
+	h := NewHash(WithPoly(0x8005), WithInit(0xffff), WithReflected(true), WithByteOrder(binary.LittleEndian))
+	h.Write(checkData)
+	if got := h.Sum(nil); string(got) != "\x37\x4b" {
+		t.Errorf("WithByteOrder: got % x, want 37 4b", got)
+	}
+}
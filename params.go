@@ -0,0 +1,198 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Params describes a CRC-16 algorithm using the parameters of the
+// Rocksoft model, as used by the CRC RevEng catalogue. See
+// http://reveng.sourceforge.net/crc-catalogue/16.htm for the parameters
+// of the standard CRC-16 algorithms.
+type Params struct {
+	// Name is the name of the algorithm, such as "MODBUS".
+	Name string
+	// Poly is the generator polynomial in normal (most significant bit
+	// first) notation, without the leading x^16 term.
+	Poly uint16
+	// Init is the initial value of the register, before any reflection.
+	Init uint16
+	// RefIn reports whether input bytes are processed least significant
+	// bit first.
+	RefIn bool
+	// RefOut reports whether the register is reflected before the final XOR.
+	RefOut bool
+	// XorOut is XORed into the register to produce the checksum.
+	XorOut uint16
+}
+
+// Reverse8 returns the value of b with its bits in reversed order, as
+// applied to input bytes by reflected algorithms.
+func Reverse8(b uint8) uint8 { return bits.Reverse8(b) }
+
+// Reverse16 returns the value of v with its bits in reversed order. It
+// converts a polynomial between normal and reversed notation, so
+// Reverse16(0x1021) is 0x8408.
+func Reverse16(v uint16) uint16 { return bits.Reverse16(v) }
+
+// NormalPoly converts a polynomial from reversed notation, as taken by
+// MakeTable, to normal notation, as in Params.Poly. NormalPoly(0x8408) is
+// 0x1021.
+func NormalPoly(reversed uint16) uint16 { return Reverse16(reversed) }
+
+// ReversedPoly converts a polynomial from normal notation to the reversed
+// notation taken by MakeTable. ReversedPoly(0x1021) is 0x8408, the CCITT
+// constant.
+func ReversedPoly(normal uint16) uint16 { return Reverse16(normal) }
+
+// FinalizeRefOut converts crc, the shift register of the reflected
+// algorithm, as updated with a reflected Table, to the output bit order
+// selected by refOut: it returns crc unchanged if refOut is set and
+// reversed otherwise. Apply the final XOR to the result.
+func FinalizeRefOut(crc uint16, refOut bool) uint16 {
+	if !refOut {
+		return Reverse16(crc)
+	}
+	return crc
+}
+
+// tableParams returns the Params implemented by Update and Checksum using
+// the reflected Table tab.
+func tableParams(tab *Table) Params {
+	p := Params{Name: "custom", Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff}
+	switch tab {
+	case ANSITable:
+		p.Name = "ANSI"
+	case CCITTTable:
+		p.Name = "CCITT"
+	}
+	if tab != nil {
+		p.Poly = Reverse16(tab[0x80])
+	}
+	return p
+}
+
+// register returns the initial value of the shift register for p.
+func (p *Params) register() uint16 {
+	if p.RefIn {
+		return Reverse16(p.Init)
+	}
+	return p.Init
+}
+
+// update returns the result of adding the bytes in data to the shift
+// register crc, using the bit order of the algorithm.
+func (p *Params) update(crc uint16, tab *Table, data []byte) uint16 {
+	if p.RefIn {
+		return update(crc, tab, data)
+	}
+	return updateMSB(crc, tab, data)
+}
+
+// finish returns the checksum held by the shift register crc.
+func (p *Params) finish(crc uint16) uint16 {
+	if p.RefIn != p.RefOut {
+		crc = Reverse16(crc)
+	}
+	return crc ^ p.XorOut
+}
+
+// checksum returns the checksum of data using tab, the Table for p.
+func (p *Params) checksum(tab *Table, data []byte) uint16 {
+	return p.finish(p.update(p.register(), tab, data))
+}
+
+// TableFor returns the Table for the polynomial of p, reflected or not as
+// selected by p.RefIn. Tables are built on first use and cached, so every
+// call for the same polynomial and bit order returns the same Table.
+func TableFor(p Params) *Table {
+	if p.RefIn {
+		return MakeTableRef(Reverse16(p.Poly), true)
+	}
+	return MakeTableRef(p.Poly, false)
+}
+
+// MakeTableParams returns the Table constructed from the polynomial of p,
+// for the bit order selected by p.RefIn. It is the same as TableFor.
+func MakeTableParams(p Params) *Table { return TableFor(p) }
+
+// Check returns the check value of p: the checksum of the ASCII string
+// "123456789", listed for each algorithm in the CRC RevEng catalogue.
+func (p Params) Check() uint16 {
+	return p.checksum(TableFor(p), []byte("123456789"))
+}
+
+// NewParams creates a new Hash16 computing the CRC-16 checksum
+// using the algorithm described by p.
+func NewParams(p Params) Hash16 {
+	d := &digest{tab: TableFor(p), params: p}
+	d.Reset()
+	return d
+}
+
+// ErrPolyNotation is returned by Validate for polynomials that look like
+// they are given in reversed rather than normal notation.
+var ErrPolyNotation = errors.New("crc16: polynomial lacks the x^0 term; is it in reversed notation?")
+
+// Validate reports whether p is self-consistent. It returns ErrZeroPoly if
+// the polynomial is zero and ErrPolyNotation if its least significant bit
+// is clear.
+//
+// The check relies on the representation: in the normal notation of
+// Params the x^0 term, which every useful CRC polynomial has, is the least
+// significant bit, while in reversed notation it is the most significant.
+// A clear low bit, as in 0x8408, the reversed CCITT polynomial, therefore
+// suggests the wrong notation. Reversed polynomials with the low bit set,
+// such as 0xA001, cannot be told apart from normal ones and pass.
+func (p Params) Validate() error {
+	switch {
+	case p.Poly == 0:
+		return ErrZeroPoly
+	case p.Poly&1 == 0:
+		return ErrPolyNotation
+	}
+	return nil
+}
+
+// NewParamsChecked is like NewParams but first validates p and returns
+// the error of Validate, if any, instead of a Hash16.
+func NewParamsChecked(p Params) (Hash16, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return NewParams(p), nil
+}
+
+// FactoryParams returns a Factory creating digests as NewParams(p) does.
+// The Table for p is built once, when FactoryParams is called.
+func FactoryParams(p Params) Factory {
+	tab := TableFor(p)
+	return func() Hash16 {
+		d := &digest{tab: tab, params: p}
+		d.Reset()
+		return d
+	}
+}
+
+// ResidueOf returns the residue of p: the checksum of any message followed
+// by its own checksum, which is the same for every intact frame. The
+// checksum must follow the message in processing order, least significant
+// byte first if p.RefIn is set and most significant byte first otherwise.
+// The residue is only constant for algorithms with RefIn equal to RefOut.
+//
+// Unlike the residues listed in the CRC RevEng catalogue, the result
+// includes the final XOR, so it can be compared directly with the checksum
+// of a frame.
+func ResidueOf(p Params) uint16 {
+	tab := TableFor(p)
+	crc := p.checksum(tab, nil)
+	b := [Size]byte{byte(crc >> 8), byte(crc)}
+	if p.RefIn {
+		b[0], b[1] = b[1], b[0]
+	}
+	return p.checksum(tab, b[:])
+}
@@ -0,0 +1,189 @@
+package crc16
+
+import (
+	"testing"
+)
+
+func TestNewParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     Params
+		check uint16
+	}{
+		{"ARC", Params{Poly: 0x8005, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000}, 0xbb3d},
+		{"MODBUS", Params{Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000}, 0x4b37},
+		{"USB", Params{Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff}, 0xb4c8},
+		{"KERMIT", Params{Poly: 0x1021, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000}, 0x2189},
+		{"RIELLO", Params{Poly: 0x1021, Init: 0xb2aa, RefIn: true, RefOut: true, XorOut: 0x0000}, 0x63d0},
+		{"XMODEM", Params{Poly: 0x1021, Init: 0x0000, RefIn: false, RefOut: false, XorOut: 0x0000}, 0x31c3},
+		{"CCITT-FALSE", Params{Poly: 0x1021, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0x0000}, 0x29b1},
+		{"GENIBUS", Params{Poly: 0x1021, Init: 0xffff, RefIn: false, RefOut: false, XorOut: 0xffff}, 0xd64e},
+	}
+
+	for _, tt := range tests {
+		h := NewParams(tt.p)
+		h.Write([]byte("123456789"))
+		if got := h.Sum16(); got != tt.check {
+			t.Errorf("%s: got %#04x, want %#04x", tt.name, got, tt.check)
+		}
+
+		// The same result must be obtained when writing in pieces and
+		// after resetting the digest.
+		h.Reset()
+		h.Write([]byte("1234"))
+		h.Write([]byte("56789"))
+		if got := h.Sum16(); got != tt.check {
+			t.Errorf("%s: got %#04x after Reset, want %#04x", tt.name, got, tt.check)
+		}
+	}
+}
+
+func TestNewParamsTable(t *testing.T) {
+	data := []byte("hello world")
+	h := NewParams(tableParams(ANSITable))
+	h.Write(data)
+	if got, want := h.Sum16(), ChecksumANSI(data); got != want {
+		t.Fatalf("got %#04x, want %#04x", got, want)
+	}
+}
+
+func TestMakeTableParams(t *testing.T) {
+	modbus := Params{Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true}
+	if *MakeTableParams(modbus) != *MakeTable(0xA001) {
+		t.Fatal("reflected table does not match MakeTable of the reversed polynomial")
+	}
+}
+
+func TestDigestParams(t *testing.T) {
+	if got := NewParams(Modbus).(*digest).Params(); got != Modbus {
+		t.Errorf("got %+v, want %+v", got, Modbus)
+	}
+
+	want := Params{Name: "CCITT", Poly: 0x1021, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff}
+	if got := NewCCITT().(*digest).Params(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// The parameters of a digest reproduce its checksums.
+	for _, h := range []Hash16{NewANSI(), NewCCITT(), New(MakeTable(0x1234)), NewParams(XMODEM)} {
+		h.Write(checkData)
+		r := NewParams(h.(*digest).Params())
+		r.Write(checkData)
+		if r.Sum16() != h.Sum16() {
+			t.Errorf("%v: reproduced as %v", h, r)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	for _, tt := range []struct{ in, want uint8 }{
+		{0x01, 0x80}, {0x80, 0x01}, {0x0f, 0xf0}, {0xa5, 0xa5}, {0x12, 0x48},
+	} {
+		if got := Reverse8(tt.in); got != tt.want {
+			t.Errorf("Reverse8(%#02x) = %#02x, want %#02x", tt.in, got, tt.want)
+		}
+	}
+	for _, tt := range []struct{ in, want uint16 }{
+		{0x0001, 0x8000}, {0x1021, 0x8408}, {0x8005, 0xa001}, {0x3d65, 0xa6bc},
+	} {
+		if got := Reverse16(tt.in); got != tt.want {
+			t.Errorf("Reverse16(%#04x) = %#04x, want %#04x", tt.in, got, tt.want)
+		}
+		if got := Reverse16(tt.want); got != tt.in {
+			t.Errorf("Reverse16(%#04x) = %#04x, want %#04x", tt.want, got, tt.in)
+		}
+	}
+}
+
+func TestFactory(t *testing.T) {
+	for _, f := range []Factory{FactoryFor(ANSITable), FactoryParams(Modbus)} {
+		h1, h2 := f(), f()
+		want := h1.Sum16()
+		h1.Write(checkData)
+		if got := h2.Sum16(); got != want {
+			t.Fatalf("writing to one digest changed the other: got %#04x, want %#04x", got, want)
+		}
+		h2.Write(checkData)
+		if h1.Sum16() != h2.Sum16() {
+			t.Fatalf("digests disagree: %#04x and %#04x", h1.Sum16(), h2.Sum16())
+		}
+	}
+
+	h := FactoryParams(Modbus)()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x4b37 {
+		t.Errorf("FactoryParams(Modbus): got %#04x, want 0x4b37", got)
+	}
+}
+
+func TestFinalizeRefOut(t *testing.T) {
+	for _, p := range []Params{
+		KERMIT,
+		XMODEM,
+		{Poly: 0x1021, Init: 0x0000, RefIn: true, RefOut: false, XorOut: 0x0000},
+		{Poly: 0x8005, Init: 0xffff, RefIn: false, RefOut: true, XorOut: 0xffff},
+	} {
+		// Run the algorithm on a reflected register, whatever p.RefIn.
+		tab := MakeTableParams(p)
+		reg := p.update(p.register(), tab, checkData)
+		if !p.RefIn {
+			reg = Reverse16(reg)
+		}
+		h := NewParams(p)
+		h.Write(checkData)
+		if got, want := FinalizeRefOut(reg, p.RefOut)^p.XorOut, h.Sum16(); got != want {
+			t.Errorf("%+v: got %#04x, want %#04x", p, got, want)
+		}
+	}
+
+	if got := FinalizeRefOut(0x8408, true); got != 0x8408 {
+		t.Errorf("refOut: got %#04x, want 0x8408", got)
+	}
+	if got := FinalizeRefOut(0x8408, false); got != 0x1021 {
+		t.Errorf("no refOut: got %#04x, want 0x1021", got)
+	}
+}
+
+func TestPolyNotation(t *testing.T) {
+	for _, tt := range []struct{ normal, reversed uint16 }{
+		{0x1021, 0x8408},
+		{0x8005, 0xa001},
+		{0x3d65, 0xa6bc},
+		{0x8bb7, 0xedd1},
+	} {
+		if got := ReversedPoly(tt.normal); got != tt.reversed {
+			t.Errorf("ReversedPoly(%#04x) = %#04x, want %#04x", tt.normal, got, tt.reversed)
+		}
+		if got := NormalPoly(tt.reversed); got != tt.normal {
+			t.Errorf("NormalPoly(%#04x) = %#04x, want %#04x", tt.reversed, got, tt.normal)
+		}
+	}
+	if MakeTable(ReversedPoly(0x1021)) != CCITTTable {
+		t.Error("MakeTable(ReversedPoly(0x1021)) is not CCITTTable")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, p := range catalog {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: %v", p.Name, err)
+		}
+	}
+	for _, tt := range []struct {
+		p   Params
+		err error
+	}{
+		{Params{Poly: 0}, ErrZeroPoly},
+		{Params{Poly: 0x8408}, ErrPolyNotation},
+	} {
+		if err := tt.p.Validate(); err != tt.err {
+			t.Errorf("%#04x: got %v, want %v", tt.p.Poly, err, tt.err)
+		}
+		if h, err := NewParamsChecked(tt.p); h != nil || err != tt.err {
+			t.Errorf("%#04x: NewParamsChecked got %v, %v", tt.p.Poly, h, err)
+		}
+	}
+	if h, err := NewParamsChecked(XMODEM); err != nil || h.Sum16() != 0 {
+		t.Errorf("XMODEM: NewParamsChecked got %v", err)
+	}
+}
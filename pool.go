@@ -0,0 +1,33 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import "sync"
+
+// digestPool holds digests released by PutDigest.
+var digestPool = sync.Pool{New: func() any { return new(digest) }}
+
+// GetDigest returns a Hash16 computing the checksum of New(tab), taken
+// from a pool shared by all Tables when one is available. Release it with
+// PutDigest once it is no longer used.
+func GetDigest(tab *Table) Hash16 {
+	d := digestPool.Get().(*digest)
+	d.order = nil
+	d.ResetWith(tab)
+	return d
+}
+
+// PutDigest returns a digest obtained from GetDigest, New or NewParams to
+// the pool used by GetDigest. h must not be used afterwards. Hashes of
+// other types are ignored.
+func PutDigest(h Hash16) {
+	d, ok := h.(*digest)
+	if !ok {
+		return
+	}
+	// Drop the Table so that the pool does not keep it alive.
+	*d = digest{}
+	digestPool.Put(d)
+}
@@ -0,0 +1,58 @@
+package crc16
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestDigestPool(t *testing.T) {
+	custom := MakeTable(0xa6bc)
+	for i := 0; i < 3; i++ {
+		for _, tab := range []*Table{ANSITable, CCITTTable, custom} {
+			h := GetDigest(tab)
+			h.Write(checkData)
+			if got, want := h.Sum16(), Checksum(checkData, tab); got != want {
+				t.Errorf("%#04x: got %#04x, want %#04x", Reverse16(tab[0x80]), got, want)
+			}
+			PutDigest(h)
+		}
+	}
+
+	// A pooled digest with another byte order is returned big-endian.
+	h := NewWithOrder(CCITTTable, binary.LittleEndian)
+	PutDigest(h)
+	h = GetDigest(CCITTTable)
+	h.Write(checkData)
+	if got := h.Sum(nil); string(got) != "\x90\x6e" {
+		t.Errorf("Sum: got % x, want 90 6e", got)
+	}
+}
+
+func BenchmarkDigestPool(b *testing.B) {
+	data := make([]byte, 64)
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				h := New(ANSITable)
+				h.Write(data)
+				if h.Sum16() == 0 {
+					b.Error("zero checksum")
+				}
+			}
+		})
+	})
+	b.Run("GetDigest", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				h := GetDigest(ANSITable)
+				h.Write(data)
+				if h.Sum16() == 0 {
+					b.Error("zero checksum")
+				}
+				PutDigest(h)
+			}
+		})
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import "bytes"
+
+// A RecordHasher is an io.Writer that splits the data written to it into
+// records terminated by a delimiter byte and computes the Checksum of each
+// record, like a bufio.Scanner that also checksums.
+type RecordHasher struct {
+	tab   *Table
+	delim byte
+	fn    func(record []byte, crc uint16)
+	buf   []byte // partial record
+	crc   uint16 // checksum of buf
+}
+
+// NewRecordHasher returns a RecordHasher that calls fn with each complete
+// record, without its delimiter, and its Checksum using the Table. The
+// record is only valid until fn returns.
+func NewRecordHasher(tab *Table, delim byte, fn func(record []byte, crc uint16)) *RecordHasher {
+	return &RecordHasher{tab: tab, delim: delim, fn: fn}
+}
+
+// Write adds p to the data, calling the record function for each record
+// completed by p. The bytes after the last delimiter are kept until the
+// next Write or Flush. It always returns len(p), nil.
+func (h *RecordHasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for {
+		i := bytes.IndexByte(p, h.delim)
+		if i < 0 {
+			h.buf = append(h.buf, p...)
+			h.crc = Update(h.crc, h.tab, p)
+			return n, nil
+		}
+		rec := p[:i]
+		crc := Update(h.crc, h.tab, rec)
+		if len(h.buf) > 0 {
+			h.buf = append(h.buf, rec...)
+			rec = h.buf
+		}
+		h.fn(rec, crc)
+		h.buf, h.crc = h.buf[:0], 0
+		p = p[i+1:]
+	}
+}
+
+// Flush calls the record function for the final record if the data does
+// not end with a delimiter.
+func (h *RecordHasher) Flush() {
+	if len(h.buf) > 0 {
+		h.fn(h.buf, h.crc)
+		h.buf, h.crc = h.buf[:0], 0
+	}
+}
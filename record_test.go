@@ -0,0 +1,40 @@
+package crc16
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestRecordHasher(t *testing.T) {
+	data := []byte("first record\n\nsecond record\n123456789\nunterminated")
+	want := bytes.Split(data, []byte("\n"))
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		var got [][]byte
+		h := NewRecordHasher(CCITTTable, '\n', func(rec []byte, crc uint16) {
+			if want := ChecksumCCITT(rec); crc != want {
+				t.Errorf("%q: got %#04x, want %#04x", rec, crc, want)
+			}
+			got = append(got, append([]byte{}, rec...))
+		})
+
+		// Write in chunks of random sizes, including empty ones.
+		for p := data; len(p) > 0; {
+			n := rnd.Intn(min(len(p), 16) + 1)
+			h.Write(p[:n])
+			p = p[n:]
+		}
+		h.Flush()
+
+		if len(got) != len(want) {
+			t.Fatalf("got %d records, want %d", len(got), len(want))
+		}
+		for j := range want {
+			if !bytes.Equal(got[j], want[j]) {
+				t.Fatalf("record %d: got %q, want %q", j, got[j], want[j])
+			}
+		}
+	}
+}
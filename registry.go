@@ -0,0 +1,44 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import "sync"
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes the Factory available under name to Get. Names are
+// matched as in ByName. The catalog algorithms are registered under their
+// names and aliases. Register panics if f is nil or if name is already
+// registered.
+func Register(name string, f Factory) {
+	if f == nil {
+		panic("crc16: Register factory is nil")
+	}
+	key := normalizeName(name)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := byName[key]; ok {
+		panic("crc16: Register called twice for " + name)
+	}
+	if _, ok := registry[key]; ok {
+		panic("crc16: Register called twice for " + name)
+	}
+	registry[key] = f
+}
+
+// Get returns the Factory registered under name, if any.
+func Get(name string) (Factory, bool) {
+	key := normalizeName(name)
+	if p, ok := byName[key]; ok {
+		return func() Hash16 { return NewParams(p) }, true
+	}
+	registryMu.RLock()
+	f, ok := registry[key]
+	registryMu.RUnlock()
+	return f, ok
+}
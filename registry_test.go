@@ -0,0 +1,44 @@
+package crc16
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	f, ok := Get("CRC-16/MODBUS")
+	if !ok {
+		t.Fatal("MODBUS is not registered")
+	}
+	h := f()
+	h.Write(checkData)
+	if got := h.Sum16(); got != 0x4b37 {
+		t.Errorf("MODBUS: got %#04x, want 0x4b37", got)
+	}
+
+	custom := Params{Name: "CRC-16/TEST-REGISTRY", Poly: 0x3d65, Init: 0x1234, RefIn: true, RefOut: true}
+	// The registry outlives the test, so it may be run more than once.
+	if _, ok := Get(custom.Name); !ok {
+		Register(custom.Name, FactoryParams(custom))
+	}
+	f, ok = Get("test_registry")
+	if !ok {
+		t.Fatal("custom variant is not registered")
+	}
+	h = f()
+	h.Write(checkData)
+	if got, want := h.Sum16(), custom.Check(); got != want {
+		t.Errorf("custom: got %#04x, want %#04x", got, want)
+	}
+
+	if _, ok := Get("no-such-crc"); ok {
+		t.Error("Get found an unregistered name")
+	}
+	for _, name := range []string{"modbus", custom.Name} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("registering %s twice did not panic", name)
+				}
+			}()
+			Register(name, FactoryParams(custom))
+		}()
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+// A Rolling computes the Checksum of a sliding window of bytes, updated in
+// constant time as bytes enter and leave the window. It is meant for
+// content-defined chunking and similar uses; like any CRC, it offers no
+// protection against deliberate collisions.
+type Rolling struct {
+	tab    *Table
+	window int
+	out    [256]uint16 // contribution of each byte leaving the window
+	k      uint16      // effect of the initial value and final XOR
+	crc    uint16      // shift register of the window from zero
+}
+
+// NewRolling returns a Rolling computing the Checksum, using the Table, of
+// a window of the given size. The window initially holds zero bytes.
+// NewRolling panics if window is less than 1.
+func NewRolling(tab *Table, window int) *Rolling {
+	if window < 1 {
+		panic("crc16: invalid rolling window size")
+	}
+	r := &Rolling{tab: tab, window: window}
+	// A byte leaving the window has been followed by window-1 bytes; its
+	// contribution is that of the byte followed by as many zeros.
+	for i := range r.out {
+		r.out[i] = shift(tab[i], int64(window-1), tab, true)
+	}
+	r.k = shift(0xffff, int64(window), tab, true) ^ 0xffff
+	return r
+}
+
+// Window returns the size of the window.
+func (r *Rolling) Window() int { return r.window }
+
+// Roll adds in to the window and removes out, the byte added window calls
+// earlier, or zero during the first window calls. It returns the Checksum
+// of the bytes in the window.
+func (r *Rolling) Roll(in, out byte) uint16 {
+	crc := r.crc ^ r.out[out]
+	r.crc = r.tab[byte(crc)^in] ^ (crc >> 8)
+	return r.crc ^ r.k
+}
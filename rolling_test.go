@@ -0,0 +1,28 @@
+package crc16
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRolling(t *testing.T) {
+	data := make([]byte, 1000)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, tab := range []*Table{ANSITable, CCITTTable} {
+		for _, window := range []int{1, 2, 16, 48} {
+			r := NewRolling(tab, window)
+			if r.Window() != window {
+				t.Fatalf("Window: got %d, want %d", r.Window(), window)
+			}
+			// The window starts filled with zeros.
+			buf := append(make([]byte, window), data...)
+			for i, in := range data {
+				got := r.Roll(in, buf[i])
+				if want := Checksum(buf[i+1:i+1+window], tab); got != want {
+					t.Fatalf("window %d, byte %d: got %#04x, want %#04x", window, i, got, want)
+				}
+			}
+		}
+	}
+}
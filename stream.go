@@ -0,0 +1,264 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+)
+
+// bufferSize is the size of the scratch buffers used to read streams.
+const bufferSize = 32 * 1024
+
+var bufferPool = sync.Pool{
+	New: func() any { return new([bufferSize]byte) },
+}
+
+// ReadFrom adds the data read from r until EOF to the checksum. It
+// implements io.ReaderFrom, so io.Copy to the digest avoids an
+// intermediate buffer. The return value n is the number of bytes read.
+// Any error except io.EOF encountered during the read is also returned.
+func (d *digest) ReadFrom(r io.Reader) (n int64, err error) {
+	buf := bufferPool.Get().(*[bufferSize]byte)
+	defer bufferPool.Put(buf)
+	for {
+		m, err := r.Read(buf[:])
+		if m > 0 {
+			d.add(buf[:m])
+			n += int64(m)
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// A Writer is an io.Writer that computes the CRC-16 checksum of the data
+// written to an underlying writer.
+type Writer struct {
+	w   io.Writer
+	crc uint16
+	tab *Table
+}
+
+// NewWriter returns a Writer that writes to w and computes the checksum of
+// the written data using the Table, as Checksum does.
+func NewWriter(w io.Writer, tab *Table) *Writer {
+	return &Writer{w: w, tab: tab}
+}
+
+// Write writes p to the underlying writer and adds the bytes written to the
+// checksum.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	n, err = w.w.Write(p)
+	w.crc = Update(w.crc, w.tab, p[:n])
+	return n, err
+}
+
+// Sum16 returns the checksum of the data written so far.
+func (w *Writer) Sum16() uint16 { return w.crc }
+
+// A TrailerWriter is a Writer that writes the checksum of the data to the
+// underlying writer when closed.
+type TrailerWriter struct {
+	Writer
+	order  binary.ByteOrder
+	closed bool
+}
+
+// ErrWriterClosed is returned by TrailerWriter.Write after Close.
+var ErrWriterClosed = errors.New("crc16: write after Close")
+
+// NewTrailerWriter returns a TrailerWriter that writes to w, computing the
+// checksum using the Table and writing it in the given byte order.
+func NewTrailerWriter(w io.Writer, tab *Table, order binary.ByteOrder) *TrailerWriter {
+	return &TrailerWriter{Writer: Writer{w: w, tab: tab}, order: order}
+}
+
+// Write is like Writer.Write but returns ErrWriterClosed after Close.
+func (w *TrailerWriter) Write(p []byte) (n int, err error) {
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	return w.Writer.Write(p)
+}
+
+// Close writes the checksum of the data written so far to the underlying
+// writer, which is not closed. It returns the error of that write, or
+// io.ErrShortWrite if the checksum was only partly written. Later calls
+// write nothing and return nil.
+func (w *TrailerWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	var b [Size]byte
+	putUint16(b[:], w.Sum16(), w.order)
+	n, err := w.w.Write(b[:])
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// A Reader is an io.Reader that computes the CRC-16 checksum of the data
+// read from an underlying reader.
+type Reader struct {
+	r   io.Reader
+	crc uint16
+	tab *Table
+}
+
+// NewReader returns a Reader that reads from r and computes the checksum
+// of the data read using the Table, as Checksum does.
+func NewReader(r io.Reader, tab *Table) *Reader {
+	return &Reader{r: r, tab: tab}
+}
+
+// Read reads from the underlying reader into p and adds the bytes read to
+// the checksum.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if n > 0 {
+		r.crc = Update(r.crc, r.tab, p[:n])
+	}
+	return n, err
+}
+
+// Sum16 returns the checksum of the data read so far.
+func (r *Reader) Sum16() uint16 { return r.crc }
+
+// ChecksumReader returns the CRC-16 checksum, as computed by Checksum, and
+// the length of the data read from r until EOF. If reading fails, it
+// returns the checksum and length of the data read before the error.
+func ChecksumReader(r io.Reader, tab *Table) (uint16, int64, error) {
+	d := New(tab).(*digest)
+	n, err := d.ReadFrom(r)
+	return d.Sum16(), n, err
+}
+
+// progressInterval is the number of bytes between the progress reports of
+// ChecksumReaderProgress.
+const progressInterval = 1 << 20
+
+// ChecksumReaderProgress is like ChecksumReader but calls cb with the
+// number of bytes read so far each time another MiB has been read, and
+// once more with the total when reading stops. Since cb runs in the read
+// loop, it should return quickly.
+func ChecksumReaderProgress(r io.Reader, tab *Table, cb func(bytesRead int64)) (uint16, int64, error) {
+	pr := &progressReader{r: r, next: progressInterval, reported: -1, cb: cb}
+	crc, n, err := ChecksumReader(pr, tab)
+	if pr.reported != n {
+		cb(n)
+	}
+	return crc, n, err
+}
+
+// progressReader reports the progress of reading from r to cb.
+type progressReader struct {
+	r        io.Reader
+	n        int64 // bytes read
+	next     int64 // count at which to report next
+	reported int64 // count last reported
+	cb       func(int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	m, err := r.r.Read(p)
+	if r.n += int64(m); r.n >= r.next {
+		r.cb(r.n)
+		r.reported = r.n
+		r.next = r.n + progressInterval
+	}
+	return m, err
+}
+
+// ChecksumFile returns the CRC-16 checksum, as computed by Checksum, of the
+// contents of the named file.
+func ChecksumFile(path string, tab *Table) (uint16, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	crc, _, err := ChecksumReader(f, tab)
+	return crc, err
+}
+
+// ChecksumContext is like ChecksumReader but stops reading and returns
+// ctx.Err() if ctx is done. The context is checked before each read, so a
+// read that blocks is not interrupted.
+func ChecksumContext(ctx context.Context, r io.Reader, tab *Table) (uint16, int64, error) {
+	buf := bufferPool.Get().(*[bufferSize]byte)
+	defer bufferPool.Put(buf)
+	var crc uint16
+	var n int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return crc, n, err
+		}
+		m, err := r.Read(buf[:])
+		if m > 0 {
+			crc = Update(crc, tab, buf[:m])
+			n += int64(m)
+		}
+		if err == io.EOF {
+			return crc, n, nil
+		}
+		if err != nil {
+			return crc, n, err
+		}
+	}
+}
+
+// ChecksumFrom returns the CRC-16 checksum, as computed by Checksum, of the
+// data that src writes with its WriteTo method. If WriteTo fails, it
+// returns the checksum of the data written before the error.
+func ChecksumFrom(src io.WriterTo, tab *Table) (uint16, error) {
+	w := NewWriter(io.Discard, tab)
+	_, err := src.WriteTo(w)
+	return w.Sum16(), err
+}
+
+// ChecksumStruct returns the Checksum of v, encoded as by binary.Write in
+// the byte order order, or big-endian if order is nil. As for binary.Write,
+// v must be a fixed-size value, or a slice or pointer to fixed-size values;
+// otherwise ChecksumStruct returns the error of binary.Write.
+func ChecksumStruct(v any, order binary.ByteOrder, tab *Table) (uint16, error) {
+	if order == nil {
+		order = binary.BigEndian
+	}
+	d := New(tab)
+	if err := binary.Write(d, order, v); err != nil {
+		return 0, err
+	}
+	return d.Sum16(), nil
+}
+
+// WriteBuffers adds the contents of each buffer in bufs to the checksum,
+// in order, as if they were written as one slice.
+func (d *digest) WriteBuffers(bufs net.Buffers) {
+	for _, b := range bufs {
+		d.add(b)
+	}
+}
+
+// ChecksumBuffers returns the Checksum, using the Table, of the
+// concatenation of the buffers in bufs, without copying them.
+func ChecksumBuffers(bufs net.Buffers, tab *Table) uint16 {
+	var crc uint16
+	for _, b := range bufs {
+		crc = Update(crc, tab, b)
+	}
+	return crc
+}
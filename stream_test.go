@@ -0,0 +1,352 @@
+package crc16
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadFrom(t *testing.T) {
+	data := make([]byte, 5<<20+123)
+	rand.New(rand.NewSource(1)).Read(data)
+	want := ChecksumANSI(data)
+
+	h := NewANSI()
+	n, err := h.(io.ReaderFrom).ReadFrom(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("read %d bytes, want %d", n, len(data))
+	}
+	if got := h.Sum16(); got != want {
+		t.Fatalf("got %#04x, want %#04x", got, want)
+	}
+
+	// io.Copy uses ReadFrom; hide the WriterTo of the source to be sure.
+	h.Reset()
+	if _, err := io.Copy(h, struct{ io.Reader }{bytes.NewReader(data)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Sum16(); got != want {
+		t.Fatalf("io.Copy: got %#04x, want %#04x", got, want)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, CCITTTable)
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("data was not forwarded")
+	}
+	if got, want := w.Sum16(), Checksum(data, CCITTTable); got != want {
+		t.Fatalf("got %#04x, want %#04x", got, want)
+	}
+}
+
+func TestReader(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	// OneByteReader and DataErrReader exercise short reads and data
+	// returned together with io.EOF.
+	for _, src := range []io.Reader{
+		bytes.NewReader(data),
+		iotest.OneByteReader(bytes.NewReader(data)),
+		iotest.DataErrReader(bytes.NewReader(data)),
+	} {
+		r := NewReader(src, ANSITable)
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatal("data was not forwarded")
+		}
+		if got, want := r.Sum16(), ChecksumANSI(data); got != want {
+			t.Fatalf("got %#04x, want %#04x", got, want)
+		}
+	}
+}
+
+func TestChecksumReader(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	crc, n, err := ChecksumReader(bytes.NewReader(data), ANSITable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) || crc != ChecksumANSI(data) {
+		t.Fatalf("got %#04x, %d bytes; want %#04x, %d bytes", crc, n, ChecksumANSI(data), len(data))
+	}
+
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(data[:1000]), iotest.ErrReader(errRead))
+	crc, n, err = ChecksumReader(r, ANSITable)
+	if err != errRead {
+		t.Fatalf("got error %v, want %v", err, errRead)
+	}
+	if n != 1000 || crc != ChecksumANSI(data[:1000]) {
+		t.Fatalf("got %#04x, %d bytes; want %#04x, 1000 bytes", crc, n, ChecksumANSI(data[:1000]))
+	}
+}
+
+// chunkWriterTo writes its chunks one at a time, then fails with err.
+type chunkWriterTo struct {
+	chunks [][]byte
+	err    error
+}
+
+func (c chunkWriterTo) WriteTo(w io.Writer) (n int64, err error) {
+	for _, chunk := range c.chunks {
+		m, err := w.Write(chunk)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, c.err
+}
+
+func TestChecksumFrom(t *testing.T) {
+	src := chunkWriterTo{chunks: [][]byte{[]byte("1234"), []byte("5"), nil, []byte("6789")}}
+	crc, err := ChecksumFrom(src, ANSITable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ChecksumANSI(checkData); crc != want {
+		t.Fatalf("got %#04x, want %#04x", crc, want)
+	}
+
+	src.err = errors.New("write failed")
+	crc, err = ChecksumFrom(src, ANSITable)
+	if err != src.err {
+		t.Fatalf("got error %v, want %v", err, src.err)
+	}
+	if want := ChecksumANSI(checkData); crc != want {
+		t.Fatalf("got %#04x after error, want %#04x", crc, want)
+	}
+}
+
+func TestChecksumStruct(t *testing.T) {
+	type header struct {
+		Version uint8
+		Flags   uint8
+		Length  uint16
+		Seq     uint32
+		Coords  [2]int16
+	}
+	v := header{Version: 1, Flags: 0x80, Length: 512, Seq: 0xdeadbeef, Coords: [2]int16{-1, 7}}
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		var buf bytes.Buffer
+		binary.Write(&buf, order, &v)
+		got, err := ChecksumStruct(&v, order, ANSITable)
+		if want := Checksum(buf.Bytes(), ANSITable); err != nil || got != want {
+			t.Errorf("%v: got %#04x, %v; want %#04x", order, got, err, want)
+		}
+	}
+	a, _ := ChecksumStruct(v, nil, ANSITable)
+	if b, _ := ChecksumStruct(v, binary.BigEndian, ANSITable); a != b {
+		t.Error("nil order is not big-endian")
+	}
+
+	type variable struct {
+		Length uint16
+		Data   []byte
+	}
+	if _, err := ChecksumStruct(variable{}, binary.BigEndian, ANSITable); err == nil {
+		t.Error("variable-size struct accepted")
+	}
+}
+
+func TestChecksumBuffers(t *testing.T) {
+	data := make([]byte, 1000)
+	rand.New(rand.NewSource(1)).Read(data)
+	want := ChecksumANSI(data)
+
+	for _, splits := range [][]int{
+		{},
+		{0},
+		{0, 0, 1000},
+		{1, 2, 3, 17, 17, 500},
+		{999, 1000, 1000},
+	} {
+		var bufs net.Buffers
+		i := 0
+		for _, j := range splits {
+			bufs = append(bufs, data[i:j])
+			i = j
+		}
+		bufs = append(bufs, data[i:], nil)
+
+		if got := ChecksumBuffers(bufs, ANSITable); got != want {
+			t.Errorf("splits %v: got %#04x, want %#04x", splits, got, want)
+		}
+		d := NewANSI().(*digest)
+		d.WriteBuffers(bufs)
+		if got := d.Sum16(); got != want {
+			t.Errorf("splits %v: WriteBuffers got %#04x, want %#04x", splits, got, want)
+		}
+	}
+}
+
+// cancelReader calls cancel once n bytes have been read from r.
+type cancelReader struct {
+	r      io.Reader
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancelReader) Read(p []byte) (int, error) {
+	m, err := c.r.Read(p[:min(len(p), c.n)])
+	if c.n -= m; c.n == 0 {
+		c.cancel()
+	}
+	return m, err
+}
+
+func TestChecksumContext(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	crc, n, err := ChecksumContext(context.Background(), bytes.NewReader(data), ANSITable)
+	if err != nil || n != int64(len(data)) || crc != ChecksumANSI(data) {
+		t.Fatalf("got %#04x, %d bytes, %v", crc, n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := &cancelReader{r: bytes.NewReader(data), n: 1000, cancel: cancel}
+	crc, n, err = ChecksumContext(ctx, r, ANSITable)
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if n != 1000 || crc != ChecksumANSI(data[:1000]) {
+		t.Fatalf("got %#04x, %d bytes; want %#04x, 1000 bytes", crc, n, ChecksumANSI(data[:1000]))
+	}
+}
+
+func TestChecksumFile(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(data)
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	crc, err := ChecksumFile(path, CCITTTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ChecksumCCITT(data); crc != want {
+		t.Fatalf("got %#04x, want %#04x", crc, want)
+	}
+
+	if _, err := ChecksumFile(filepath.Join(t.TempDir(), "missing"), CCITTTable); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("missing file: got error %v, want os.ErrNotExist", err)
+	}
+}
+
+// limitWriter accepts n bytes, then fails with err.
+type limitWriter struct {
+	n   int
+	err error
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, w.err
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestTrailerWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewTrailerWriter(&buf, CCITTTable, binary.BigEndian)
+	io.WriteString(w, "12345")
+	io.WriteString(w, "6789")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(buf.Bytes(), CCITTTable) {
+		t.Fatalf("Verify rejected % x", buf.Bytes())
+	}
+
+	buf.Reset()
+	w = NewTrailerWriter(&buf, CCITTTable, binary.LittleEndian)
+	w.Write(checkData)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "123456789\x6e\x90" {
+		t.Fatalf("got % x", got)
+	}
+
+	// Closing again, as with a deferred Close, writes no second trailer,
+	// and later writes are rejected.
+	if err := w.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if n, err := w.Write(checkData); n != 0 || err != ErrWriterClosed {
+		t.Fatalf("Write after Close: got %d, %v; want ErrWriterClosed", n, err)
+	}
+	if got := buf.String(); got != "123456789\x6e\x90" {
+		t.Fatalf("after second Close: got % x", got)
+	}
+
+	errWrite := errors.New("write failed")
+	w = NewTrailerWriter(&limitWriter{n: 10, err: errWrite}, CCITTTable, binary.BigEndian)
+	w.Write(checkData)
+	if err := w.Close(); err != errWrite {
+		t.Fatalf("got error %v, want %v", err, errWrite)
+	}
+	w = NewTrailerWriter(&limitWriter{n: 10}, CCITTTable, binary.BigEndian)
+	w.Write(checkData)
+	if err := w.Close(); err != io.ErrShortWrite {
+		t.Fatalf("got error %v, want io.ErrShortWrite", err)
+	}
+}
+
+func TestChecksumReaderProgress(t *testing.T) {
+	for _, size := range []int{0, 1000, 5<<20 + 123} {
+		data := make([]byte, size)
+		rand.New(rand.NewSource(1)).Read(data)
+
+		var counts []int64
+		crc, n, err := ChecksumReaderProgress(bytes.NewReader(data), ANSITable, func(bytesRead int64) {
+			counts = append(counts, bytesRead)
+		})
+		if err != nil || n != int64(size) || crc != ChecksumANSI(data) {
+			t.Fatalf("size %d: got %#04x, %d bytes, %v", size, crc, n, err)
+		}
+		if len(counts) == 0 || counts[len(counts)-1] != int64(size) {
+			t.Fatalf("size %d: progress %v does not end with the total", size, counts)
+		}
+		for i := 1; i < len(counts); i++ {
+			if counts[i] <= counts[i-1] {
+				t.Fatalf("size %d: progress %v is not increasing", size, counts)
+			}
+		}
+		if want := size/progressInterval + 1; len(counts) != want {
+			t.Errorf("size %d: got %d reports, want %d", size, len(counts), want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Sum is a CRC-16 checksum that is represented in text, such as JSON, as a
+// hexadecimal string of the form "0x1A2B".
+type Sum uint16
+
+// String returns s as "0x" followed by four uppercase hexadecimal digits.
+func (s Sum) String() string {
+	b, _ := s.MarshalText()
+	return string(b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Sum) MarshalText() ([]byte, error) {
+	const digits = "0123456789ABCDEF"
+	return []byte{'0', 'x', digits[s>>12], digits[s>>8&0xf], digits[s>>4&0xf], digits[s&0xf]}, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts hexadecimal
+// digits in either case, with or without a "0x" prefix.
+func (s *Sum) UnmarshalText(text []byte) error {
+	t := string(text)
+	if strings.HasPrefix(t, "0x") || strings.HasPrefix(t, "0X") {
+		t = t[2:]
+	}
+	v, err := strconv.ParseUint(t, 16, 16)
+	if err != nil {
+		return errors.New("crc16: invalid checksum " + strconv.Quote(string(text)))
+	}
+	*s = Sum(v)
+	return nil
+}
+
+// FormatSum returns s in the given base, which must be between 2 and 36.
+// Base 16 is formatted as "0x" followed by four digits; other bases have
+// no prefix or padding. Letter digits are uppercase if upper is set.
+func FormatSum(s uint16, base int, upper bool) string {
+	var t string
+	if base == 16 {
+		// Bit 16 pads the digits to four; its own digit is dropped.
+		t = strconv.FormatUint(uint64(s)|1<<16, 16)[1:]
+	} else {
+		t = strconv.FormatUint(uint64(s), base)
+	}
+	if upper {
+		t = strings.ToUpper(t)
+	}
+	if base == 16 {
+		t = "0x" + t
+	}
+	return t
+}
+
+// ParseSum parses a checksum formatted by FormatSum in base 10 or 16. The
+// text is hexadecimal if it has a "0x" prefix or contains a letter digit,
+// and decimal otherwise, so hexadecimal checksums made only of decimal
+// digits, such as "1234", need the prefix.
+func ParseSum(text string) (uint16, error) {
+	t, base := text, 10
+	if strings.HasPrefix(t, "0x") || strings.HasPrefix(t, "0X") {
+		t, base = t[2:], 16
+	} else if strings.ContainsAny(t, "abcdefABCDEF") {
+		base = 16
+	}
+	v, err := strconv.ParseUint(t, base, 16)
+	if err != nil {
+		return 0, errors.New("crc16: invalid checksum " + strconv.Quote(text))
+	}
+	return uint16(v), nil
+}
+
+// SumValue returns the checksum as a Sum.
+func (d *digest) SumValue() Sum { return Sum(d.Sum16()) }
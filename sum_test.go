@@ -0,0 +1,84 @@
+package crc16
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSumText(t *testing.T) {
+	b, err := json.Marshal(struct{ CRC Sum }{0x1a2b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"CRC":"0x1A2B"}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	for _, text := range []string{"1A2B", "0x1a2b", "0X1A2B"} {
+		var s Sum
+		if err := s.UnmarshalText([]byte(text)); err != nil {
+			t.Errorf("%q: %v", text, err)
+		} else if s != 0x1a2b {
+			t.Errorf("%q: got %v, want 0x1A2B", text, s)
+		}
+	}
+
+	for _, text := range []string{"", "0x", "10000", "0x1FFFF", "-1", "12G4", "0x 1"} {
+		var s Sum
+		if err := s.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("%q: accepted as %v", text, s)
+		}
+	}
+}
+
+func TestSumValue(t *testing.T) {
+	h := NewParams(Modbus)
+	h.Write(checkData)
+	if got := h.(*digest).SumValue(); got != 0x4b37 {
+		t.Fatalf("got %v, want 0x4B37", got)
+	}
+}
+
+func TestFormatSum(t *testing.T) {
+	for _, tt := range []struct {
+		s     uint16
+		base  int
+		upper bool
+		want  string
+	}{
+		{0x1a2b, 16, true, "0x1A2B"},
+		{0x1a2b, 16, false, "0x1a2b"},
+		{0x1a2b, 10, false, "6699"},
+		{0x000f, 16, true, "0x000F"},
+		{0, 10, false, "0"},
+		{0xffff, 2, false, "1111111111111111"},
+	} {
+		if got := FormatSum(tt.s, tt.base, tt.upper); got != tt.want {
+			t.Errorf("FormatSum(%#04x, %d, %v) = %q, want %q", tt.s, tt.base, tt.upper, got, tt.want)
+		}
+	}
+	if got := FormatSum(0x1a2b, 16, true); got != Sum(0x1a2b).String() {
+		t.Errorf("FormatSum differs from Sum.String: %q", got)
+	}
+}
+
+func TestParseSum(t *testing.T) {
+	for _, s := range []uint16{0, 1, 0x1a2b, 0x8005, 0xffff} {
+		for _, text := range []string{FormatSum(s, 16, true), FormatSum(s, 16, false), FormatSum(s, 10, false)} {
+			if got, err := ParseSum(text); err != nil || got != s {
+				t.Errorf("%q: got %#04x, %v; want %#04x", text, got, err, s)
+			}
+		}
+	}
+	for text, want := range map[string]uint16{"1a2b": 0x1a2b, "FFFF": 0xffff, "0X1A2B": 0x1a2b, "1234": 1234} {
+		if got, err := ParseSum(text); err != nil || got != want {
+			t.Errorf("%q: got %#04x, %v; want %#04x", text, got, err, want)
+		}
+	}
+
+	for _, text := range []string{"", "0x", "65536", "0x10000", "-1", "12G4", "0x 1", " 12"} {
+		if got, err := ParseSum(text); err == nil {
+			t.Errorf("%q: accepted as %#04x", text, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2014 Michael Phan-Ba <michael@mikepb.com>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc16
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrShortFrame is returned for frames too short to hold a checksum.
+var ErrShortFrame = errors.New("crc16: frame shorter than checksum")
+
+// Append appends the Checksum of frame using the Table to frame, big-endian
+// as Sum does, and returns the extended slice. The result is accepted by
+// Verify.
+func Append(frame []byte, tab *Table) []byte {
+	crc := Checksum(frame, tab)
+	return append(frame, byte(crc>>8), byte(crc))
+}
+
+// Verify reports whether the last two bytes of frame hold the Checksum of
+// the preceding bytes using the Table. The trailing checksum is stored
+// big-endian, as appended by Sum.
+func Verify(frame []byte, tab *Table) bool {
+	ok, _, _ := Check(frame, tab)
+	return ok
+}
+
+// Check is like Verify but also returns want, the checksum stored in the
+// last two bytes of frame, and got, the Checksum of the preceding bytes,
+// so that a mismatch can be reported. Frames shorter than Size are
+// rejected with want and got set to zero.
+func Check(frame []byte, tab *Table) (ok bool, want, got uint16) {
+	n := len(frame) - Size
+	if n < 0 {
+		return false, 0, 0
+	}
+	want = uint16(frame[n])<<8 | uint16(frame[n+1])
+	got = Checksum(frame[:n], tab)
+	return got == want, want, got
+}
+
+// VerifyFrame reports whether the last two bytes of frame, decoded in the
+// byte order order, or big-endian if order is nil, hold the Checksum of
+// the preceding bytes using the Table. If they do not, the error is a
+// *CRCError holding both checksums. It returns ErrShortFrame if frame is
+// shorter than Size.
+func VerifyFrame(frame []byte, tab *Table, order binary.ByteOrder) (bool, error) {
+	want, payload, err := DecodeCRC(frame, order)
+	if err != nil {
+		return false, err
+	}
+	if got := Checksum(payload, tab); want != got {
+		return false, &CRCError{Want: want, Got: got}
+	}
+	return true, nil
+}
+
+// DecodeCRC splits frame into its payload and the checksum stored in its
+// last two bytes, decoded in the byte order order, or big-endian if order
+// is nil. The payload aliases frame. It returns ErrShortFrame if frame is
+// shorter than Size.
+func DecodeCRC(frame []byte, order binary.ByteOrder) (crc uint16, payload []byte, err error) {
+	n := len(frame) - Size
+	if n < 0 {
+		return 0, nil, ErrShortFrame
+	}
+	if order == nil {
+		order = binary.BigEndian
+	}
+	return order.Uint16(frame[n:]), frame[:n], nil
+}
+
+// VerifyResidue reports whether frame, which ends with the Checksum of the
+// preceding bytes stored little-endian, is intact. It computes the Checksum
+// of the whole frame and compares it with the residue of the Table, the
+// constant result for any correctly terminated frame.
+//
+// The little-endian order feeds the checksum bits in the order processed
+// by the reflected algorithm; frames ending with a big-endian checksum
+// must be checked with Verify instead.
+func VerifyResidue(frame []byte, tab *Table) bool {
+	if len(frame) < Size {
+		return false
+	}
+	// The residue is the checksum of the empty message followed by its
+	// checksum, which is zero.
+	return Checksum(frame, tab) == Checksum([]byte{0, 0}, tab)
+}
+
+// VerifyResidueParams is like VerifyResidue for the algorithm described
+// by p. The frame must end with its checksum stored least significant
+// byte first if p.RefIn is set and most significant byte first otherwise,
+// so that the checksum of the whole frame is ResidueOf(p). As for
+// ResidueOf, p.RefIn must equal p.RefOut.
+func VerifyResidueParams(frame []byte, p Params) bool {
+	if len(frame) < Size {
+		return false
+	}
+	return p.checksum(TableFor(p), frame) == ResidueOf(p)
+}
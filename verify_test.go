@@ -0,0 +1,159 @@
+package crc16
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	data := []byte("hello world")
+	for _, tab := range []*Table{ANSITable, CCITTTable} {
+		crc := Checksum(data, tab)
+		be := append(append([]byte{}, data...), byte(crc>>8), byte(crc))
+		le := append(append([]byte{}, data...), byte(crc), byte(crc>>8))
+
+		if !Verify(be, tab) {
+			t.Error("Verify rejected a big-endian frame")
+		}
+		if Verify(le, tab) {
+			t.Error("Verify accepted a little-endian frame")
+		}
+		if !VerifyResidue(le, tab) {
+			t.Error("VerifyResidue rejected a little-endian frame")
+		}
+		if VerifyResidue(be, tab) {
+			t.Error("VerifyResidue accepted a big-endian frame")
+		}
+
+		be[0] ^= 1
+		le[0] ^= 1
+		if Verify(be, tab) || VerifyResidue(le, tab) {
+			t.Error("corrupted frame accepted")
+		}
+	}
+
+	if Verify([]byte{0}, ANSITable) || VerifyResidue([]byte{0}, ANSITable) {
+		t.Error("short frame accepted")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	frame := append([]byte("123456789"), 0x90, 0x6e)
+	if ok, want, got := Check(frame, CCITTTable); !ok || want != 0x906e || got != 0x906e {
+		t.Errorf("intact frame: got %v, %#04x, %#04x", ok, want, got)
+	}
+
+	frame[len(frame)-1] = 0x6f
+	if ok, want, got := Check(frame, CCITTTable); ok || want != 0x906f || got != 0x906e {
+		t.Errorf("corrupted frame: got %v, %#04x, %#04x", ok, want, got)
+	}
+
+	if ok, want, got := Check([]byte{0x90}, CCITTTable); ok || want != 0 || got != 0 {
+		t.Errorf("short frame: got %v, %#04x, %#04x", ok, want, got)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	for _, tab := range []*Table{ANSITable, CCITTTable, MakeTable(0xa001)} {
+		data := []byte("hello world")
+		frame := Append(data[:len(data):len(data)], tab)
+		if !Verify(frame, tab) {
+			t.Errorf("Verify rejected % x", frame)
+		}
+		h := New(tab)
+		h.Write(data)
+		if want := h.Sum(data); string(frame) != string(want) {
+			t.Errorf("got % x, want % x", frame, want)
+		}
+	}
+	if got := Append(nil, CCITTTable); string(got) != "\x00\x00" {
+		t.Errorf("empty frame: got % x", got)
+	}
+}
+
+func TestVerifyFrame(t *testing.T) {
+	be := []byte("123456789\x90\x6e")
+	le := []byte("123456789\x6e\x90")
+	tests := []struct {
+		frame []byte
+		order binary.ByteOrder
+		ok    bool
+	}{
+		{be, binary.BigEndian, true},
+		{be, binary.LittleEndian, false},
+		{le, binary.LittleEndian, true},
+		{le, binary.BigEndian, false},
+		{[]byte{0, 0}, binary.BigEndian, true},
+		{be, nil, true},
+		{le, nil, false},
+	}
+	for _, tt := range tests {
+		ok, err := VerifyFrame(tt.frame, CCITTTable, tt.order)
+		if ok != tt.ok || (err == nil) != tt.ok {
+			t.Errorf("% x, %v: got %v, %v; want %v", tt.frame, tt.order, ok, err, tt.ok)
+		}
+	}
+
+	_, err := VerifyFrame(le, CCITTTable, binary.BigEndian)
+	var crcErr *CRCError
+	if !errors.As(err, &crcErr) || crcErr.Want != 0x6e90 || crcErr.Got != 0x906e {
+		t.Errorf("got error %v, want a *CRCError", err)
+	}
+	if !errors.Is(err, ErrMismatch) {
+		t.Errorf("error %v is not ErrMismatch", err)
+	}
+
+	for _, frame := range [][]byte{nil, {0x90}} {
+		if ok, err := VerifyFrame(frame, CCITTTable, binary.BigEndian); ok || err != ErrShortFrame {
+			t.Errorf("% x: got %v, %v; want ErrShortFrame", frame, ok, err)
+		}
+	}
+}
+
+func TestDecodeCRC(t *testing.T) {
+	frame := []byte("123456789\x90\x6e")
+	for _, tt := range []struct {
+		order binary.ByteOrder
+		want  uint16
+	}{
+		{binary.BigEndian, 0x906e},
+		{binary.LittleEndian, 0x6e90},
+		{nil, 0x906e},
+	} {
+		crc, payload, err := DecodeCRC(frame, tt.order)
+		if err != nil || crc != tt.want || string(payload) != "123456789" {
+			t.Errorf("%v: got %#04x, %q, %v; want %#04x", tt.order, crc, payload, err, tt.want)
+		}
+	}
+	if crc, _, _ := DecodeCRC(frame, binary.BigEndian); crc != ChecksumCCITT(checkData) {
+		t.Errorf("decoded %#04x, want the CCITT check value", crc)
+	}
+
+	for _, frame := range [][]byte{nil, {0x90}} {
+		if _, _, err := DecodeCRC(frame, binary.BigEndian); err != ErrShortFrame {
+			t.Errorf("% x: got %v, want ErrShortFrame", frame, err)
+		}
+	}
+}
+
+func TestVerifyResidueParams(t *testing.T) {
+	data := []byte("hello world")
+	for _, p := range catalog {
+		crc := p.checksum(TableFor(*p), data)
+		frame := append(append([]byte{}, data...), byte(crc>>8), byte(crc))
+		if p.RefIn {
+			frame[len(data)], frame[len(data)+1] = byte(crc), byte(crc>>8)
+		}
+		if !VerifyResidueParams(frame, *p) {
+			t.Errorf("%s: frame rejected", p.Name)
+		}
+		frame[0] ^= 1
+		if VerifyResidueParams(frame, *p) {
+			t.Errorf("%s: corrupted frame accepted", p.Name)
+		}
+	}
+	if VerifyResidueParams([]byte{0}, Profibus) {
+		t.Error("short frame accepted")
+	}
+}